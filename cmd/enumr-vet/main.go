@@ -0,0 +1,21 @@
+// Command enumr-vet runs the enumr analyzers, which check enumr directives and
+// how enums generated by enumr are used. Run it through go vet:
+//
+//	go vet -vettool=$(which enumr-vet) ./...
+//
+// or directly on package patterns:
+//
+//	enumr-vet ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/jmfrees/go-enumr/pkg/analysis/directive"
+	"github.com/jmfrees/go-enumr/pkg/analysis/exhaustive"
+	"github.com/jmfrees/go-enumr/pkg/analysis/immutable"
+)
+
+func main() {
+	multichecker.Main(directive.Analyzer, exhaustive.Analyzer, immutable.Analyzer)
+}
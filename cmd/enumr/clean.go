@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// runClean implements the "clean" subcommand. It deletes generated files
+// whose types no longer exist or no longer have instances, and returns the
+// process exit code.
+func runClean(args []string) int {
+	fs := flag.NewFlagSet("clean", flag.ExitOnError)
+	dryRun := fs.Bool("n", false, "print the files that would be removed without removing them")
+	diagnostics := addDiagnosticsFlag(fs)
+
+	_ = fs.Parse(args)
+
+	logger, flush, err := newLogger(*diagnostics, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer flush()
+	ctx := context.Background()
+	report := reportOutput(*diagnostics)
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	dirs, err := packageDirs(patterns)
+	if err != nil {
+		logger.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"Error loading packages",
+			slog.String("rule", enumr.RuleLoad),
+			slog.Any("error", err),
+		)
+		return 1
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		generated, err := enumr.GeneratedFiles(dir)
+		if err != nil {
+			logger.ErrorContext(ctx, "Error scanning directory", "dir", dir, "error", err)
+			failed = true
+			continue
+		}
+
+		for _, file := range generated {
+			if !file.Orphaned() {
+				// Deleting the file would also delete the types that still
+				// need it, so leave it to be regenerated instead.
+				logger.WarnContext(
+					ctx,
+					"Generated file has types without instances; regenerate it",
+					"file", relativePath(file.Path),
+					"types", file.Missing,
+				)
+				continue
+			}
+
+			if *dryRun {
+				fmt.Fprintf(report, "rm %s\n", relativePath(file.Path))
+				continue
+			}
+			if err = os.Remove(file.Path); err != nil {
+				logger.ErrorContext(ctx, "Error removing file", "file", file.Path, "error", err)
+				failed = true
+				continue
+			}
+			fmt.Fprintf(report, "removed %s\n", relativePath(file.Path))
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// packageDirs returns the directories of the packages matched by the
+// patterns. Only file lists are loaded, so packages that no longer build
+// because of an orphaned file are still found.
+func packageDirs(patterns []string) ([]string, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) == 0 {
+			continue
+		}
+		if dir := filepath.Dir(pkg.GoFiles[0]); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// runCompat implements the "compat" subcommand. It compares the enums in the
+// current package against a baseline and returns the process exit code.
+func runCompat(args []string) int {
+	fs := flag.NewFlagSet("compat", flag.ExitOnError)
+	typeNames := fs.String("type", "", "comma-separated list type(s) to check (required)")
+	base := fs.String("base", "", "baseline to compare against: a snapshot file or a git ref")
+	export := fs.String("export", "", "write the current snapshot to this file instead of comparing")
+	strict := fs.Bool("strict", false, "treat changed field values as breaking")
+	diagnostics := addDiagnosticsFlag(fs)
+	opts := addOptionFlags(fs)
+
+	_ = fs.Parse(args)
+
+	logger, flush, err := newLogger(*diagnostics, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer flush()
+	ctx := context.Background()
+	report := reportOutput(*diagnostics)
+
+	if len(*typeNames) == 0 {
+		logger.ErrorContext(ctx, "argument is required", "arg", "-type")
+		return 2
+	}
+	if len(*base) == 0 && len(*export) == 0 {
+		logger.ErrorContext(ctx, "argument is required", "arg", "-base")
+		return 2
+	}
+	targetTypes := strings.Split(*typeNames, ",")
+
+	dir, err := packageDir(fs.Args())
+	if err != nil {
+		logger.ErrorContext(ctx, "Error checking directory", "error", err)
+		return 1
+	}
+
+	pkg, err := loadPackageFromDir(dir)
+	if err != nil {
+		logger.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"Error loading package",
+			slog.String("rule", enumr.RuleLoad),
+			slog.Any("error", err),
+		)
+		return 1
+	}
+
+	generator := enumr.NewGenerator(logger)
+	current, err := generator.Snapshot(ctx, pkg, targetTypes, *opts)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error resolving enums", "error", err)
+		return 1
+	}
+
+	if len(*export) > 0 {
+		if err = writeSnapshot(*export, current); err != nil {
+			logger.ErrorContext(ctx, "Error writing snapshot", "file", *export, "error", err)
+			return 1
+		}
+		return 0
+	}
+
+	baseline, err := loadBaseline(ctx, generator, dir, *base, targetTypes, *opts)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error loading baseline", "base", *base, "error", err)
+		return 1
+	}
+
+	// Only the requested types are compared, so a snapshot covering more
+	// types can be reused across invocations.
+	baseline.Types = slices.DeleteFunc(baseline.Types, func(t enumr.TypeSnapshot) bool {
+		return !slices.Contains(targetTypes, t.Name)
+	})
+
+	breaking := false
+	for _, change := range enumr.Compare(baseline, current) {
+		if *strict && change.Kind == enumr.ChangeFields {
+			change.Breaking = true
+		}
+		if change.Breaking {
+			breaking = true
+			fmt.Fprintf(report, "BREAKING %s\n", change)
+		} else {
+			fmt.Fprintf(report, "ok       %s\n", change)
+		}
+	}
+
+	if breaking {
+		return 1
+	}
+	return 0
+}
+
+// loadBaseline reads the baseline snapshot. If base names an existing file it
+// is decoded as a snapshot; otherwise it is treated as a git ref and the
+// package is resolved as it exists at that ref.
+func loadBaseline(
+	ctx context.Context,
+	generator *enumr.Generator,
+	dir, base string,
+	typeNames []string,
+	opts enumr.Options,
+) (*enumr.Snapshot, error) {
+	if f, err := os.Open(base); err == nil {
+		defer f.Close()
+		return enumr.ReadSnapshot(f)
+	}
+
+	pkg, tmpDir, err := loadPackageAtRef(ctx, dir, base)
+	if tmpDir != "" {
+		defer os.RemoveAll(tmpDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Types missing at the baseline are new, not removed, so only snapshot
+	// those that already existed.
+	var existing []string
+	for _, typeName := range typeNames {
+		if pkg.Types == nil || pkg.Types.Scope().Lookup(typeName) != nil {
+			existing = append(existing, typeName)
+		}
+	}
+	if len(existing) == 0 {
+		return &enumr.Snapshot{}, nil
+	}
+
+	return generator.Snapshot(ctx, pkg, existing, opts)
+}
+
+// loadPackageAtRef loads the package in dir as it exists at the given git ref.
+// The repository tree at ref is extracted into a temporary directory, which is
+// returned so the caller can remove it.
+func loadPackageAtRef(ctx context.Context, dir, ref string) (*packages.Package, string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out, err := exec.CommandContext(ctx, "git", "-C", absDir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find git repository for %s: %w", dir, err)
+	}
+	top := strings.TrimSpace(string(out))
+
+	rel, err := relPath(top, absDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "enumr-compat-")
+	if err != nil {
+		return nil, "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", top, "archive", "--format=tar", ref)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, tmpDir, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, tmpDir, err
+	}
+	extractErr := extractTar(stdout, tmpDir)
+	// Drain the rest of the stream so git can exit if extraction stopped early.
+	_, _ = io.Copy(io.Discard, stdout)
+	if err = cmd.Wait(); err != nil {
+		return nil, tmpDir, fmt.Errorf(
+			"failed to archive %s: %w: %s",
+			ref,
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+	if extractErr != nil {
+		return nil, tmpDir, extractErr
+	}
+
+	pkg, err := loadPackageFromDir(filepath.Join(tmpDir, rel))
+	return pkg, tmpDir, err
+}
+
+// relPath returns target relative to base, resolving symlinks so that paths
+// reported by git and the working directory agree.
+func relPath(base, target string) (string, error) {
+	if resolved, err := filepath.EvalSymlinks(base); err == nil {
+		base = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+	return filepath.Rel(base, target)
+}
+
+// extractTar writes the regular files and directories of a tar stream into dir.
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if !filepath.IsLocal(hdr.Name) {
+			continue
+		}
+
+		path := filepath.Join(dir, hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err = writeFileFrom(path, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFileFrom(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeSnapshot(path string, snapshot *enumr.Snapshot) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err = snapshot.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// ruleCommand is the rule ID of failures that are not tied to a source
+// position, such as invalid arguments or I/O errors.
+const ruleCommand = "enumr/command"
+
+// addDiagnosticsFlag registers the -diagnostics flag shared by all commands.
+func addDiagnosticsFlag(fs *flag.FlagSet) *string {
+	return fs.String(
+		"diagnostics",
+		"",
+		"report errors and warnings on stdout as \"json\" or \"sarif\" (default: log to stderr)",
+	)
+}
+
+// reportOutput returns where a command writes its plain-text report: stdout,
+// unless stdout is taken by the diagnostics of the given mode.
+func reportOutput(mode string) io.Writer {
+	if mode == "" {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+// newLogger returns the logger for the given diagnostics mode and a function
+// that writes out the collected diagnostics to w, normally stdout. In the
+// default mode messages are logged as text on stderr, keeping stdout free for
+// generated output.
+func newLogger(mode string, w io.Writer) (*slog.Logger, func(), error) {
+	var write func(io.Writer, []enumr.Diagnostic) error
+	switch mode {
+	case "":
+		return slog.New(slog.NewTextHandler(os.Stderr, nil)), func() {}, nil
+	case "json":
+		write = writeJSONDiagnostics
+	case "sarif":
+		write = writeSARIFDiagnostics
+	default:
+		return nil, nil, fmt.Errorf("unknown diagnostics format %q (want json or sarif)", mode)
+	}
+
+	state := &diagnosticState{}
+	flush := func() {
+		if err := write(w, state.diags); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write diagnostics: %v\n", err)
+		}
+	}
+	return slog.New(&diagnosticHandler{state: state}), flush, nil
+}
+
+type diagnosticState struct {
+	mu    sync.Mutex
+	diags []enumr.Diagnostic
+}
+
+// diagnosticHandler is a slog.Handler that collects warnings and errors as
+// diagnostics instead of printing them.
+type diagnosticHandler struct {
+	state *diagnosticState
+	attrs []slog.Attr
+}
+
+func (h *diagnosticHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn
+}
+
+func (h *diagnosticHandler) Handle(_ context.Context, r slog.Record) error {
+	diags := h.diagnostics(r)
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	h.state.diags = append(h.state.diags, diags...)
+	return nil
+}
+
+func (h *diagnosticHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &diagnosticHandler{state: h.state, attrs: slices.Concat(h.attrs, attrs)}
+}
+
+func (h *diagnosticHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// diagnostics converts a log record into diagnostics. Errors wrapping
+// diagnostics are reported as such; any other record becomes a diagnostic
+// built from its message, its "rule", "pos" and "fix" attributes and the
+// remaining attributes as details.
+func (h *diagnosticHandler) diagnostics(r slog.Record) []enumr.Diagnostic {
+	var diags []enumr.Diagnostic
+	var pos token.Position
+	var fix *enumr.Fix
+	rule := ruleCommand
+	details := []string{}
+
+	visit := func(a slog.Attr) bool {
+		if a.Key == "rule" {
+			rule = a.Value.String()
+			return true
+		}
+		switch v := a.Value.Any().(type) {
+		case token.Position:
+			pos = v
+		case enumr.Fix:
+			fix = &v
+		case error:
+			if carried := enumr.Diagnostics(v); len(carried) > 0 {
+				diags = append(diags, carried...)
+			} else {
+				details = append(details, v.Error())
+			}
+		default:
+			details = append(details, fmt.Sprintf("%s=%v", a.Key, a.Value))
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		visit(a)
+	}
+	r.Attrs(visit)
+
+	if len(diags) > 0 {
+		return diags
+	}
+
+	severity := enumr.SeverityWarning
+	if r.Level >= slog.LevelError {
+		severity = enumr.SeverityError
+	}
+	message := r.Message
+	if len(details) > 0 {
+		message += ": " + strings.Join(details, ", ")
+	}
+	return []enumr.Diagnostic{{Rule: rule, Severity: severity, Pos: pos, Message: message, Fix: fix}}
+}
+
+type jsonDiagnostic struct {
+	File     string         `json:"file,omitempty"`
+	Line     int            `json:"line,omitempty"`
+	Column   int            `json:"column,omitempty"`
+	Severity enumr.Severity `json:"severity"`
+	Rule     string         `json:"rule"`
+	Message  string         `json:"message"`
+}
+
+// writeJSONDiagnostics writes the diagnostics as a JSON array.
+func writeJSONDiagnostics(w io.Writer, diags []enumr.Diagnostic) error {
+	out := make([]jsonDiagnostic, 0, len(diags))
+	for _, d := range diags {
+		out = append(out, jsonDiagnostic{
+			File:     d.Pos.Filename,
+			Line:     d.Pos.Line,
+			Column:   d.Pos.Column,
+			Severity: d.Severity,
+			Rule:     d.Rule,
+			Message:  d.Message,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// The types below model the subset of SARIF 2.1.0 needed to report results.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// writeSARIFDiagnostics writes the diagnostics as a SARIF 2.1.0 log.
+func writeSARIFDiagnostics(w io.Writer, diags []enumr.Diagnostic) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "enumr",
+			InformationURI: "https://github.com/jmfrees/go-enumr",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	seen := make(map[string]bool)
+	for _, d := range diags {
+		if !seen[d.Rule] {
+			seen[d.Rule] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: d.Rule})
+		}
+
+		result := sarifResult{
+			RuleID:  d.Rule,
+			Level:   sarifLevel(d.Severity),
+			Message: sarifMessage{Text: d.Message},
+		}
+		if d.Pos.Filename != "" {
+			location := sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: sarifURI(d.Pos.Filename)},
+			}
+			if d.Pos.Line > 0 {
+				location.Region = &sarifRegion{StartLine: d.Pos.Line, StartColumn: d.Pos.Column}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifLevel(severity enumr.Severity) string {
+	if severity == enumr.SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// sarifURI returns the file path relative to the working directory when
+// possible, as code-scanning tools resolve URIs against the repository root.
+func sarifURI(path string) string {
+	return filepath.ToSlash(relativePath(path))
+}
+
+// relativePath returns path relative to the working directory if it is
+// inside it, and path unchanged otherwise.
+func relativePath(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && filepath.IsLocal(rel) {
+			return rel
+		}
+	}
+	return path
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// diagnosticsSource declares a Method enum that sets an unknown field, which
+// is a warning, and a Fee enum with two instances sharing a value, which is
+// an error.
+const diagnosticsSource = `package card
+
+// enumr:Card Code:CC Colour:red
+// enumr:Cash Code:CA
+type Method struct {
+	Code string
+}
+
+// enumr:Low  Code:LO
+// enumr:High Code:LO
+type Fee struct {
+	Code string
+}
+`
+
+// generateDiagnostics generates the enums of diagnosticsSource, reporting
+// diagnostics in the given mode, and returns what was written for them. The
+// working directory is the module root, so the source is card/card.go.
+func generateDiagnostics(t *testing.T, mode string) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	writeModule(t, dir, "card")
+	source := filepath.Join(dir, "card", "card.go")
+	if err := os.WriteFile(source, []byte(diagnosticsSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	var out bytes.Buffer
+	logger, flush, err := newLogger(mode, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := loadPackageFromDir("card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := enumr.Options{MarshalField: "Code"}
+	if _, _, ok := generateFiles(t.Context(), logger, pkg, []string{"Method", "Fee"}, "", opts); ok {
+		t.Error("generateFiles succeeded, want the duplicate value to fail it")
+	}
+	flush()
+	return out.Bytes()
+}
+
+func TestJSONDiagnostics(t *testing.T) {
+	var got []jsonDiagnostic
+	out := generateDiagnostics(t, "json")
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+
+	want := []jsonDiagnostic{
+		{Line: 3, Column: 23, Severity: enumr.SeverityWarning, Rule: enumr.RuleUnknownField},
+		{Line: 10, Column: 1, Severity: enumr.SeverityError, Rule: enumr.RuleDuplicateValue},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d:\n%s", len(got), len(want), out)
+	}
+	for i, d := range got {
+		if filepath.Base(d.File) != "card.go" || !filepath.IsAbs(d.File) {
+			t.Errorf("diagnostic %d: File = %q, want the absolute path of card.go", i, d.File)
+		}
+		if d.Line != want[i].Line || d.Column != want[i].Column {
+			t.Errorf("diagnostic %d: position = %d:%d, want %d:%d", i, d.Line, d.Column, want[i].Line, want[i].Column)
+		}
+		if d.Severity != want[i].Severity || d.Rule != want[i].Rule {
+			t.Errorf("diagnostic %d: %s %s, want %s %s", i, d.Severity, d.Rule, want[i].Severity, want[i].Rule)
+		}
+	}
+}
+
+func TestSARIFDiagnostics(t *testing.T) {
+	var got sarifLog
+	out := generateDiagnostics(t, "sarif")
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, out)
+	}
+	if got.Version != "2.1.0" || len(got.Runs) != 1 {
+		t.Fatalf("got version %q with %d runs, want 2.1.0 with 1 run:\n%s", got.Version, len(got.Runs), out)
+	}
+	run := got.Runs[0]
+
+	var rules []string
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	if len(rules) != 2 || rules[0] != enumr.RuleUnknownField || rules[1] != enumr.RuleDuplicateValue {
+		t.Errorf("rules = %v, want [%s %s]", rules, enumr.RuleUnknownField, enumr.RuleDuplicateValue)
+	}
+
+	want := []struct {
+		rule, level string
+		line, col   int
+	}{
+		{enumr.RuleUnknownField, "warning", 3, 23},
+		{enumr.RuleDuplicateValue, "error", 10, 1},
+	}
+	if len(run.Results) != len(want) {
+		t.Fatalf("got %d results, want %d:\n%s", len(run.Results), len(want), out)
+	}
+	for i, result := range run.Results {
+		if result.RuleID != want[i].rule || result.Level != want[i].level {
+			t.Errorf("result %d: %s %s, want %s %s", i, result.Level, result.RuleID, want[i].level, want[i].rule)
+		}
+		if len(result.Locations) != 1 {
+			t.Errorf("result %d: got %d locations, want 1", i, len(result.Locations))
+			continue
+		}
+		location := result.Locations[0].PhysicalLocation
+		// Code-scanning tools resolve URIs against the repository root
+		if uri := location.ArtifactLocation.URI; uri != "card/card.go" {
+			t.Errorf("result %d: URI = %q, want card/card.go", i, uri)
+		}
+		if r := location.Region; r == nil || r.StartLine != want[i].line || r.StartColumn != want[i].col {
+			t.Errorf("result %d: region = %+v, want %d:%d", i, r, want[i].line, want[i].col)
+		}
+	}
+}
+
+func TestLoadPackageErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "card")
+	broken := filepath.Join(dir, "card", "broken.go")
+	if err := os.WriteFile(broken, []byte("package card\n\nfunc {\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := loadPackageFromDir(filepath.Join(dir, "card"))
+	if err == nil {
+		t.Fatal("loadPackageFromDir succeeded, want the parse error")
+	}
+	diags := enumr.Diagnostics(err)
+	if len(diags) == 0 {
+		t.Fatalf("error carries no diagnostics: %v", err)
+	}
+	for _, d := range diags {
+		if d.Rule != enumr.RuleLoad || d.Severity != enumr.SeverityError {
+			t.Errorf("got %s %s, want %s %s", d.Severity, d.Rule, enumr.SeverityError, enumr.RuleLoad)
+		}
+		if filepath.Base(d.Pos.Filename) != "broken.go" || d.Pos.Line != 3 {
+			t.Errorf("Pos = %v, want broken.go:3", d.Pos)
+		}
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "/src/card.go:3:7", want: "/src/card.go:3:7"},
+		{in: "/src/card.go:3", want: "/src/card.go:3"},
+		{in: "/src/card.go", want: "/src/card.go"},
+		{in: "C:/src/card.go:3:7", want: "C:/src/card.go:3:7"},
+		{in: "-", want: "-"},
+		{in: "", want: "-"},
+	}
+	for _, tt := range tests {
+		if got := parsePosition(tt.in).String(); got != tt.want {
+			t.Errorf("parsePosition(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
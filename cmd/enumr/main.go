@@ -2,11 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"go/token"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -14,50 +19,111 @@ import (
 	"github.com/jmfrees/go-enumr/pkg/enumr"
 )
 
+// stdoutOutput is the -output value that writes the generated source to stdout.
+const stdoutOutput = "-"
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "compat":
+			os.Exit(runCompat(os.Args[2:]))
+		case "clean":
+			os.Exit(runClean(os.Args[2:]))
+		}
+	}
+	os.Exit(run())
+}
+
+// run generates the requested enums and returns the process exit code.
+func run() int {
 	typeNames := flag.String("type", "", "comma-separated list type(s) to generate for (required)")
-	format := flag.String(
-		"format",
-		"",
-		"format of the name for each enum instance (default: preserve case)",
-	)
 	output := flag.String(
 		"output",
 		"",
-		"output file name or directory (default: dir/<type>_enum.go)",
+		"output file name or directory, or - for stdout (default: dir/<type>_enum.go)",
+	)
+	header := flag.String("header", "", "file whose contents are placed above the generated code header, e.g. a license")
+	diagnostics := addDiagnosticsFlag(flag.CommandLine)
+	watch := flag.Bool("watch", false, "regenerate whenever the package's source files change")
+	force := flag.Bool("force", false, "regenerate even if the inputs recorded in the output are unchanged")
+	jobs := flag.Int("j", runtime.GOMAXPROCS(0), "number of packages to generate concurrently")
+	opts := addOptionFlags(flag.CommandLine)
+	flag.BoolVar(&opts.Lock, "lock", false, "verify wire values against <type>.enumr.lock")
+	flag.BoolVar(&opts.UpdateLock, "update-lock", false, "accept changed wire values and rewrite <type>.enumr.lock")
+	flag.BoolVar(&opts.ExcludeDeprecated, "exclude-deprecated", false, "leave deprecated instances out of <Type>Values")
+	flag.BoolVar(&opts.SQL, "sql", false, "generate Value and Scan methods for database/sql")
+	flag.BoolVar(&opts.Ordinal, "ordinal", false, "generate Ordinal, Compare, Next and Prev methods")
+	flag.BoolVar(&opts.Set, "set", false, "generate a <Type>Set bitset type (implies -ordinal)")
+	flag.BoolVar(&opts.Map, "map", false, "generate a generic <Type>Map array type (implies -ordinal)")
+	flag.BoolVar(&opts.Match, "match", false, "generate <Type>Cases and Match<Type> for exhaustive matching")
+	flag.BoolVar(&opts.Split, "split", false, "write each type to its own <type>_enum.go instead of one file")
+	flag.BoolVar(
+		&opts.Runtime,
+		"runtime",
+		false,
+		"return the error types of github.com/jmfrees/go-enumr, which the module must then require",
 	)
-	marshalField := flag.String("marshal-field", "", "field to use for marshaling (String/MarshalText)")
-	zero := flag.Bool("zero", false, "allow zero value (empty string) during parsing")
+	typeCheck := flag.Bool("typecheck", true, "type-check the generated code against the package before writing it")
 
 	flag.Parse()
+	opts.SkipTypeCheck = !*typeCheck
 
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	logger, flush, err := newLogger(*diagnostics, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer flush()
 	ctx := context.Background()
 
 	// Ensure the -type argument is provided
 	if len(*typeNames) == 0 {
 		logger.ErrorContext(ctx, "argument is required", "arg", "-type")
-		os.Exit(2)
+		return 2
 	}
 	targetTypes := strings.Split(*typeNames, ",")
 
-	args := flag.Args()
-	if len(args) == 0 {
-		// Default: process whole package in current directory.
-		args = []string{"."}
+	outputName := ""
+	if output != nil {
+		outputName = *output
 	}
 
-	var dir string
+	if opts.Split && outputName != "" && !isExistingDir(outputName) {
+		logger.ErrorContext(ctx, "-output must be a directory with -split")
+		return 2
+	}
+	if outputName == stdoutOutput && (*watch || *diagnostics != "") {
+		logger.ErrorContext(ctx, "-output=- cannot be combined with -watch or -diagnostics")
+		return 2
+	}
 
-	if len(args) == 1 && isDirectory(ctx, args[0], logger) {
-		dir = args[0]
-	} else {
-		dir = filepath.Dir(args[0])
+	if *header != "" {
+		content, err := os.ReadFile(*header)
+		if err != nil {
+			logger.ErrorContext(ctx, "Error reading header", "file", *header, "error", err)
+			return 1
+		}
+		opts.Header = string(content)
+	}
+
+	// Package patterns are loaded together and generated concurrently
+	if args := flag.Args(); !*watch && isPatternList(args) {
+		return runPackages(ctx, logger, args, targetTypes, outputName, *opts, *jobs, *force)
+	}
+
+	dir, err := packageDir(flag.Args())
+	if err != nil {
+		logger.ErrorContext(ctx, "Error checking directory", "error", err)
+		return 1
 	}
 
-	nameFormat := ""
-	if format != nil && len(*format) > 0 {
-		nameFormat = *format
+	if *watch {
+		return runWatch(ctx, dir, targetTypes, outputName, *opts)
+	}
+
+	// Skip loading entirely if the output was generated from the same inputs
+	if !*force && outputName != stdoutOutput && upToDate(ctx, logger, dir, outputName, targetTypes, *opts) {
+		return 0
 	}
 
 	// Load the package
@@ -67,71 +133,298 @@ func main() {
 			ctx,
 			slog.LevelError,
 			"Error loading package",
+			slog.String("rule", enumr.RuleLoad),
 			slog.Any("error", err),
 		)
-		os.Exit(1)
+		return 1
 	}
 
-	outputName := ""
-	if output != nil {
-		outputName = *output
+	if _, _, ok := generateFiles(ctx, logger, pkg, targetTypes, outputName, *opts); !ok {
+		return 1
 	}
+	return 0
+}
 
-	// Process the loaded package and files
-	generator := enumr.NewGenerator(logger)
-	source, err := generator.Generate(ctx, pkg, targetTypes, nameFormat, *marshalField, *zero)
+// upToDate reports whether the output for the package in dir was generated
+// from its current inputs, in which case the package need not be loaded. With
+// -lock or -update-lock the package is always loaded, because the lock files
+// are not part of the recorded hash and must be checked against the source.
+func upToDate(
+	ctx context.Context,
+	logger *slog.Logger,
+	dir, outputName string,
+	targetTypes []string,
+	opts enumr.Options,
+) bool {
+	if opts.Lock || opts.UpdateLock {
+		return false
+	}
+
+	current, err := enumr.OutputUpToDate(dir, outputName, targetTypes, opts)
 	if err != nil {
-		logger.ErrorContext(ctx, "Error processing file", "error", err)
-		os.Exit(1)
+		logger.LogAttrs(
+			ctx,
+			slog.LevelDebug,
+			"Could not check whether output is up to date",
+			slog.String("dir", dir),
+			slog.Any("error", err),
+		)
+	}
+	if current {
+		logger.LogAttrs(ctx, slog.LevelDebug, "Output is up to date", slog.String("dir", dir))
+	}
+	return current
+}
+
+// generateFiles generates the enums of a loaded package, writes them to the
+// output files and removes files left over from a previous grouping of the
+// types, logging any errors. Lock files are only updated once all of this
+// succeeded, and not at all when the source goes to stdout. It returns the
+// names of the written and removed files and whether generation succeeded.
+func generateFiles(
+	ctx context.Context,
+	logger *slog.Logger,
+	pkg *packages.Package,
+	targetTypes []string,
+	outputName string,
+	opts enumr.Options,
+) (written, removed []string, ok bool) {
+	generator := enumr.NewGenerator(logger)
+	var locks []enumr.LockUpdate
+	for _, out := range enumr.OutputFiles(pkg.Dir, pkg.Name, targetTypes, outputName, opts) {
+		// Process the loaded package and files
+		source, outLocks, err := generator.Generate(ctx, pkg, out.Types, opts)
+		if err != nil {
+			logger.ErrorContext(ctx, "Error processing file", "error", err)
+			return written, nil, false
+		}
+		locks = append(locks, outLocks...)
+
+		if source == nil {
+			logger.LogAttrs(ctx, slog.LevelInfo, "No enums found to generate", slog.Any("types", out.Types))
+			continue
+		}
+
+		if out.Path == stdoutOutput {
+			if _, err = os.Stdout.Write(source); err != nil {
+				logger.ErrorContext(ctx, "Error writing to stdout", "error", err)
+				return written, nil, false
+			}
+			continue
+		}
+
+		// Write the generated source to a file
+		if err = os.WriteFile(out.Path, source, 0o644); err != nil {
+			logger.ErrorContext(ctx, "Error writing file", "file", out.Path, "error", err)
+			return written, nil, false
+		}
+
+		logger.LogAttrs(
+			ctx,
+			slog.LevelDebug,
+			"Enum generation completed successfully",
+			slog.String("file", out.Path),
+		)
+		written = append(written, out.Path)
 	}
 
-	if source == nil {
-		logger.LogAttrs(ctx, slog.LevelInfo, "No enums found to generate")
-		return
+	removed, ok = removeStaleOutputs(ctx, logger, pkg.Name, targetTypes, written)
+	if !ok || outputName == stdoutOutput {
+		return written, removed, ok
 	}
 
-	// Determine output filename
-	outFileName := enumr.GetOutputFilename(pkg.Dir, targetTypes[0], outputName)
+	for _, lock := range locks {
+		if err := lock.Write(); err != nil {
+			logger.ErrorContext(ctx, "Error writing lock file", "file", lock.Path, "error", err)
+			return written, removed, false
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "Updated lock file", slog.String("file", lock.Path))
+	}
+	return written, removed, true
+}
 
-	// Write the generated source to a file
-	if err = os.WriteFile(outFileName, source, 0o644); err != nil {
-		logger.ErrorContext(ctx, "Error writing file", "file", outFileName, "error", err)
-		os.Exit(1)
+// removeStaleOutputs removes the files generated for the target types that
+// are not among the files just written, such as per-type files after
+// switching to a combined file.
+func removeStaleOutputs(
+	ctx context.Context,
+	logger *slog.Logger,
+	packageName string,
+	targetTypes, written []string,
+) ([]string, bool) {
+	var dirs []string
+	for _, file := range written {
+		if dir := filepath.Dir(file); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
 	}
 
-	logger.LogAttrs(
-		ctx,
-		slog.LevelDebug,
-		"Enum generation completed successfully",
-		slog.String("file", outFileName),
+	var removed []string
+	for _, dir := range dirs {
+		stale, err := enumr.StaleOutputs(dir, packageName, targetTypes, written)
+		if err != nil {
+			logger.WarnContext(ctx, "Could not check for stale generated files", "dir", dir, "error", err)
+			continue
+		}
+		for _, file := range stale {
+			if err = os.Remove(file); err != nil {
+				logger.ErrorContext(ctx, "Error removing stale file", "file", file, "error", err)
+				return removed, false
+			}
+			logger.LogAttrs(ctx, slog.LevelInfo, "Removed stale generated file", slog.String("file", file))
+			removed = append(removed, file)
+		}
+	}
+	return removed, true
+}
+
+// addOptionFlags registers the flags shared by all commands that resolve enums.
+func addOptionFlags(fs *flag.FlagSet) *enumr.Options {
+	opts := &enumr.Options{}
+	fs.StringVar(
+		&opts.Format,
+		"format",
+		"",
+		"format of the name for each enum instance (default: preserve case)",
 	)
+	fs.StringVar(&opts.MarshalField, "marshal-field", "", "field to use for marshaling (String/MarshalText)")
+	fs.BoolVar(&opts.IncludeZero, "zero", false, "allow zero value (empty string) during parsing")
+	fs.StringVar(
+		&opts.Parse,
+		"parse",
+		"",
+		"default parse mode: comma-separated exact, fold, trim, nfc or nfkc (default: exact)",
+	)
+	return opts
+}
+
+// packageDir determines the package directory from the positional arguments.
+func packageDir(args []string) (string, error) {
+	if len(args) == 0 {
+		// Default: process whole package in current directory.
+		return ".", nil
+	}
+
+	if len(args) == 1 {
+		isDir, err := isDirectory(args[0])
+		if err != nil {
+			return "", err
+		}
+		if isDir {
+			return args[0], nil
+		}
+	}
+	return filepath.Dir(args[0]), nil
 }
 
 func loadPackageFromDir(dir string) (*packages.Package, error) {
-	cfg := &packages.Config{
+	return loadPackage(&packages.Config{
 		Mode:  packages.LoadSyntax, // Load syntax only
 		Tests: false,               // Ignore tests for now
-	}
+		Dir:   dir,                 // Resolve the package within its own module
+	})
+}
 
+// loadPackage loads the package in cfg.Dir.
+func loadPackage(cfg *packages.Config) (*packages.Package, error) {
 	// Load all Go files in the directory
-	packagesList, err := packages.Load(cfg, dir)
+	packagesList, err := packages.Load(cfg, ".")
 	if err != nil {
-		return nil, fmt.Errorf("failed to load package from directory %s: %w", dir, err)
+		return nil, fmt.Errorf("failed to load package from directory %s: %w", cfg.Dir, err)
 	}
 
 	if len(packagesList) == 0 {
-		return nil, fmt.Errorf("no packages found in directory %s", dir)
+		return nil, fmt.Errorf("no packages found in directory %s", cfg.Dir)
+	}
+
+	pkg := packagesList[0]
+	if err = packageErrors(pkg, cfg.Dir); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// packageErrors returns the errors that kept the package's files from being
+// listed or parsed as enumr/load diagnostics. Relative file names are resolved
+// against dir, the directory the package was loaded from. Type errors are not
+// reported: code that refers to enums not generated yet, or a stale generated
+// file, is expected to fail type checking until enumr has run.
+func packageErrors(pkg *packages.Package, dir string) error {
+	var errs []error
+	for _, e := range pkg.Errors {
+		if e.Kind == packages.TypeError {
+			continue
+		}
+		if e.Pos != "" {
+			errs = append(errs, loadDiagnostic(parsePosition(e.Pos), dir, e.Msg))
+			continue
+		}
+		// The go command reports compiler errors in its message, one per
+		// line after a "# package" header
+		found := false
+		for line := range strings.Lines(e.Msg) {
+			prefix, msg, ok := strings.Cut(strings.TrimSpace(line), ": ")
+			if pos := parsePosition(prefix); ok && pos.Line > 0 {
+				errs = append(errs, loadDiagnostic(pos, dir, msg))
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, loadDiagnostic(token.Position{}, dir, e.Msg))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadDiagnostic returns an enumr/load error at pos, with a relative file name
+// resolved against dir.
+func loadDiagnostic(pos token.Position, dir, msg string) *enumr.Diagnostic {
+	if pos.Filename != "" && !filepath.IsAbs(pos.Filename) {
+		if abs, err := filepath.Abs(filepath.Join(dir, pos.Filename)); err == nil {
+			pos.Filename = abs
+		}
 	}
+	return &enumr.Diagnostic{Rule: enumr.RuleLoad, Severity: enumr.SeverityError, Pos: pos, Message: msg}
+}
 
-	return packagesList[0], nil
+// parsePosition parses a "file:line:col" position as reported by go/packages.
+// Missing or malformed parts are left zero.
+func parsePosition(s string) token.Position {
+	var pos token.Position
+	if s == "" || s == "-" {
+		return pos
+	}
+	rest, last, ok := cutLastNumber(s)
+	if !ok {
+		pos.Filename = s
+		return pos
+	}
+	if file, line, ok := cutLastNumber(rest); ok {
+		pos.Filename, pos.Line, pos.Column = file, line, last
+	} else {
+		pos.Filename, pos.Line = rest, last
+	}
+	return pos
+}
+
+// cutLastNumber splits s at its last colon if a number follows it.
+func cutLastNumber(s string) (string, int, bool) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return s, 0, false
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return s, 0, false
+	}
+	return s[:i], n, true
 }
 
 // isDirectory reports whether the named file is a directory.
-func isDirectory(ctx context.Context, name string, logger *slog.Logger) bool {
+func isDirectory(name string) (bool, error) {
 	info, err := os.Stat(name)
 	if err != nil {
-		logger.ErrorContext(ctx, "Error checking directory", "error", err)
-		os.Exit(1)
+		return false, err
 	}
-	return info.IsDir()
+	return info.IsDir(), nil
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+func TestGenerateFilesLock(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "card")
+	pkgDir := filepath.Join(dir, "card")
+	pkg, err := loadPackageFromDir(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.DiscardHandler)
+	targetTypes := []string{"Method"}
+	opts := enumr.Options{MarshalField: "Code", Lock: true}
+	lock := enumr.LockFilename(pkgDir, "Method")
+
+	// A failed write must not leave the lock ahead of the generated code
+	missing := filepath.Join(pkgDir, "missing", "method_enum.go")
+	if _, _, ok := generateFiles(t.Context(), logger, pkg, targetTypes, missing, opts); ok {
+		t.Fatal("generateFiles into a missing directory succeeded")
+	}
+	if _, err = os.Stat(lock); !os.IsNotExist(err) {
+		t.Fatalf("lock file written despite the failed output: %v", err)
+	}
+
+	// Nothing is written to the package when the source goes to stdout
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	orig := os.Stdout
+	os.Stdout = stdout
+	_, _, ok := generateFiles(t.Context(), logger, pkg, targetTypes, stdoutOutput, opts)
+	os.Stdout = orig
+	if !ok {
+		t.Fatal("generateFiles to stdout failed")
+	}
+	if _, err = os.Stat(lock); !os.IsNotExist(err) {
+		t.Fatalf("lock file written for stdout output: %v", err)
+	}
+
+	if _, _, ok = generateFiles(t.Context(), logger, pkg, targetTypes, "", opts); !ok {
+		t.Fatal("generateFiles failed")
+	}
+	locked, err := enumr.ReadLock(lock)
+	if err != nil {
+		t.Fatalf("lock file not written: %v", err)
+	}
+	if len(locked.Entries) != 2 {
+		t.Errorf("lock = %+v; want entries for Card and Cash", locked)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// isPatternList reports whether the arguments name packages to be loaded
+// together, rather than a single directory or a list of files in one package.
+func isPatternList(args []string) bool {
+	for _, arg := range args {
+		if strings.Contains(arg, "...") {
+			return true
+		}
+	}
+	if len(args) <= 1 {
+		return false
+	}
+	for _, arg := range args {
+		if !strings.HasSuffix(arg, ".go") {
+			return true
+		}
+	}
+	return false
+}
+
+// packageJob is the generation of the target types declared in one package.
+type packageJob struct {
+	pkg   *packages.Package
+	types []string
+}
+
+// packageResult holds the outcome of a packageJob and the log records it
+// produced, which are replayed in package order once all jobs are done.
+type packageResult struct {
+	ok      bool
+	records *recordBuffer
+}
+
+// runPackages generates the target types in every package matched by the
+// patterns. All packages are loaded at once and then generated by a bounded
+// pool of workers. Log output is emitted in package order, and the exit code
+// reflects failures in any package.
+func runPackages(
+	ctx context.Context,
+	logger *slog.Logger,
+	patterns []string,
+	targetTypes []string,
+	outputName string,
+	opts enumr.Options,
+	jobs int,
+	force bool,
+) int {
+	if outputName != "" && !isExistingDir(outputName) {
+		logger.ErrorContext(ctx, "-output must be a directory when generating multiple packages")
+		return 2
+	}
+
+	var toLoad []string
+	skipped := 0
+	for _, pattern := range patterns {
+		if isExistingDir(pattern) {
+			// Skip loading entirely if the output was generated from the same inputs
+			if !force && upToDate(ctx, logger, pattern, outputName, targetTypes, opts) {
+				skipped++
+				continue
+			}
+			if !filepath.IsAbs(pattern) && !strings.HasPrefix(pattern, ".") {
+				pattern = "./" + pattern
+			}
+		}
+		toLoad = append(toLoad, pattern)
+	}
+	if len(toLoad) == 0 {
+		return 0
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadSyntax}, toLoad...)
+	if err != nil {
+		logger.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"Error loading packages",
+			slog.String("rule", enumr.RuleLoad),
+			slog.Any("error", err),
+		)
+		return 1
+	}
+
+	loadFailed := false
+	for _, pkg := range pkgs {
+		if err = packageErrors(pkg, ""); err != nil {
+			loadFailed = true
+			logger.LogAttrs(
+				ctx,
+				slog.LevelError,
+				"Error loading package",
+				slog.String("rule", enumr.RuleLoad),
+				slog.String("package", pkg.PkgPath),
+				slog.Any("error", err),
+			)
+		}
+	}
+	if loadFailed {
+		return 1
+	}
+
+	var work []packageJob
+	for _, pkg := range pkgs {
+		var types []string
+		for _, typeName := range targetTypes {
+			if pkg.Types != nil && pkg.Types.Scope().Lookup(typeName) != nil {
+				types = append(types, typeName)
+			}
+		}
+		if len(types) > 0 {
+			work = append(work, packageJob{pkg: pkg, types: types})
+		}
+	}
+	// Outputs of several packages would overwrite each other in one directory,
+	// and files with different package clauses cannot share it anyway
+	if outputName != "" && len(work)+skipped > 1 {
+		logger.ErrorContext(
+			ctx,
+			"-output cannot be used when several packages declare the types",
+			"packages", len(work)+skipped,
+		)
+		return 2
+	}
+	if len(work) == 0 {
+		if skipped > 0 {
+			return 0
+		}
+		logger.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"None of the types were found in the matched packages",
+			slog.String("rule", enumr.RuleTypeNotFound),
+			slog.Any("types", targetTypes),
+		)
+		return 1
+	}
+
+	results := generatePackages(ctx, logger.Handler(), work, outputName, opts, jobs)
+
+	failed := 0
+	for i, result := range results {
+		result.records.replay(ctx, logger.Handler())
+		if !result.ok {
+			failed++
+			logger.LogAttrs(
+				ctx,
+				slog.LevelDebug,
+				"Package failed",
+				slog.String("package", work[i].pkg.PkgPath),
+			)
+		}
+	}
+	if failed > 0 {
+		logger.ErrorContext(ctx, "Generation failed", "failed", failed, "packages", len(work))
+		return 1
+	}
+	return 0
+}
+
+// generatePackages runs the jobs on at most n workers and returns their
+// results in job order.
+func generatePackages(
+	ctx context.Context,
+	handler slog.Handler,
+	work []packageJob,
+	outputName string,
+	opts enumr.Options,
+	n int,
+) []packageResult {
+	results := make([]packageResult, len(work))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(1, min(n, len(work))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				job := work[i]
+				records := newRecordBuffer(handler)
+				logger := slog.New(records).With(slog.String("package", job.pkg.PkgPath))
+				_, _, ok := generateFiles(ctx, logger, job.pkg, job.types, outputName, opts)
+				results[i] = packageResult{ok: ok, records: records}
+			}
+		}()
+	}
+
+	for i := range work {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// recordBuffer is a slog.Handler that stores records so that output from
+// concurrent work can be emitted in a deterministic order.
+type recordBuffer struct {
+	target  slog.Handler
+	attrs   []slog.Attr
+	records *[]slog.Record
+}
+
+func newRecordBuffer(target slog.Handler) *recordBuffer {
+	return &recordBuffer{target: target, records: &[]slog.Record{}}
+}
+
+func (b *recordBuffer) Enabled(ctx context.Context, level slog.Level) bool {
+	return b.target.Enabled(ctx, level)
+}
+
+func (b *recordBuffer) Handle(_ context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(b.attrs...)
+	*b.records = append(*b.records, r)
+	return nil
+}
+
+func (b *recordBuffer) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordBuffer{
+		target:  b.target,
+		attrs:   append(b.attrs[:len(b.attrs):len(b.attrs)], attrs...),
+		records: b.records,
+	}
+}
+
+func (b *recordBuffer) WithGroup(string) slog.Handler {
+	return b
+}
+
+// replay emits the stored records to the handler.
+func (b *recordBuffer) replay(ctx context.Context, handler slog.Handler) {
+	for _, r := range *b.records {
+		_ = handler.Handle(ctx, r)
+	}
+}
+
+// isExistingDir reports whether path names an existing directory.
+func isExistingDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
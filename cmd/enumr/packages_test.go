@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// writeModule writes a module with a package per name in dir, each declaring
+// a Method enum.
+func writeModule(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	files := map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.24\n",
+	}
+	for _, name := range names {
+		files[filepath.Join(name, name+".go")] = "package " + name + `
+
+// enumr:Card Code:CC
+// enumr:Cash Code:CA
+type Method struct {
+	Code string
+}
+`
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRunPackagesOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "card", "cash")
+	out := filepath.Join(dir, "out")
+	if err := os.Mkdir(out, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	logger := slog.New(slog.DiscardHandler)
+	opts := enumr.Options{MarshalField: "Code"}
+
+	if code := runPackages(t.Context(), logger, []string{"./..."}, []string{"Method"}, out, opts, 2, true); code != 2 {
+		t.Errorf("runPackages() with -output and two packages = %d; want 2", code)
+	}
+	if entries, _ := os.ReadDir(out); len(entries) != 0 {
+		t.Errorf("runPackages() wrote %d files to -output; want none", len(entries))
+	}
+
+	if code := runPackages(t.Context(), logger, []string{"./card"}, []string{"Method"}, out, opts, 2, true); code != 0 {
+		t.Fatalf("runPackages() with -output and one package = %d; want 0", code)
+	}
+	if _, err := os.Stat(filepath.Join(out, "method_enum.go")); err != nil {
+		t.Errorf("output of one package not written to -output: %v", err)
+	}
+}
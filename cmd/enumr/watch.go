@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// watchInterval is how often watch mode polls the package directory.
+const watchInterval = 500 * time.Millisecond
+
+// fileStamp identifies a version of a watched file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// parsedFile is a cached syntax tree together with the source it was parsed
+// from and the file its positions refer to.
+type parsedFile struct {
+	src  []byte
+	file *ast.File
+	tok  *token.File
+	err  error
+}
+
+// watcher regenerates the enums of a single package. It keeps the syntax
+// trees of unchanged files between runs so that only edited files are parsed
+// again when the package is reloaded.
+type watcher struct {
+	dir     string
+	outputs map[string]bool
+	cfg     *packages.Config
+
+	mu     sync.Mutex
+	parsed map[string]parsedFile
+}
+
+func newWatcher(dir string) *watcher {
+	w := &watcher{
+		dir:     dir,
+		outputs: make(map[string]bool),
+		parsed:  make(map[string]parsedFile),
+	}
+	w.cfg = &packages.Config{
+		Mode:      packages.LoadSyntax,
+		Dir:       dir,
+		ParseFile: w.parseFile,
+	}
+	return w
+}
+
+// runWatch regenerates the enums whenever a source file of the package in dir
+// changes, until interrupted. Diagnostics are printed concisely on stderr.
+func runWatch(
+	ctx context.Context,
+	dir string,
+	targetTypes []string,
+	outputName string,
+	opts enumr.Options,
+) int {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	w := newWatcher(dir)
+	fmt.Fprintf(os.Stderr, "enumr: watching %s for changes (interrupt to stop)\n", dir)
+
+	var seen map[string]fileStamp
+	for {
+		var err error
+		if seen, _, err = w.poll(ctx, os.Stderr, seen, targetTypes, outputName, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "enumr: %v\n", err)
+			return 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// poll scans the package and regenerates its enums if the source files
+// differ from seen, the stamps of the previous poll. It returns the stamps to
+// pass to the next poll and whether it regenerated.
+func (w *watcher) poll(
+	ctx context.Context,
+	out io.Writer,
+	seen map[string]fileStamp,
+	targetTypes []string,
+	outputName string,
+	opts enumr.Options,
+) (map[string]fileStamp, bool, error) {
+	stamps, err := w.scan()
+	if err != nil {
+		return seen, false, err
+	}
+	if maps.Equal(stamps, seen) {
+		return seen, false, nil
+	}
+	w.generate(ctx, out, targetTypes, outputName, opts)
+	// Generated files are not inputs, so writing them must not trigger another run
+	maps.DeleteFunc(stamps, func(path string, _ fileStamp) bool { return w.outputs[path] })
+	return stamps, true, nil
+}
+
+// scan returns the stamps of the package's Go source files, excluding tests
+// and the files written or removed by previous runs.
+func (w *watcher) scan() (map[string]fileStamp, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	stamps := make(map[string]fileStamp, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		path, err := filepath.Abs(filepath.Join(w.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if w.outputs[path] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps, nil
+}
+
+// generate reloads the package, regenerates its enums and prints the outcome.
+// The files it writes or removes are excluded from later scans.
+func (w *watcher) generate(
+	ctx context.Context,
+	out io.Writer,
+	targetTypes []string,
+	outputName string,
+	opts enumr.Options,
+) {
+	state := &diagnosticState{}
+	logger := slog.New(&diagnosticHandler{state: state})
+
+	start := time.Now()
+	var written, removed []string
+	ok := false
+	w.cfg.Fset = w.newFileSet()
+	pkg, err := loadPackage(w.cfg)
+	if err != nil {
+		logger.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"Error loading package",
+			slog.String("rule", enumr.RuleLoad),
+			slog.Any("error", err),
+		)
+	} else {
+		written, removed, ok = generateFiles(ctx, logger, pkg, targetTypes, outputName, opts)
+	}
+	for _, file := range slices.Concat(written, removed) {
+		if path, err := filepath.Abs(file); err == nil {
+			w.outputs[path] = true
+		}
+	}
+
+	for _, d := range state.diags {
+		fmt.Fprintln(out, formatDiagnostic(d))
+	}
+	switch {
+	case !ok:
+		fmt.Fprintf(out, "%s generation failed\n", start.Format(time.TimeOnly))
+	case len(written) > 0:
+		names := make([]string, len(written))
+		for i, file := range written {
+			names[i] = relativePath(file)
+		}
+		fmt.Fprintf(
+			out,
+			"%s wrote %s in %s\n",
+			start.Format(time.TimeOnly),
+			strings.Join(names, ", "),
+			time.Since(start).Round(time.Millisecond),
+		)
+	}
+}
+
+// newFileSet returns the file set for the next load, so that the files of
+// earlier loads do not accumulate. It holds the files of the cached syntax
+// trees at their original bases, which keeps their positions valid. Cache
+// entries of files that no longer exist are dropped.
+func (w *watcher) newFileSet() *token.FileSet {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var files []*token.File
+	for path, cached := range w.parsed {
+		if _, err := os.Stat(path); err != nil {
+			delete(w.parsed, path)
+			continue
+		}
+		if cached.tok != nil {
+			files = append(files, cached.tok)
+		}
+	}
+	slices.SortFunc(files, func(a, b *token.File) int { return cmp.Compare(a.Base(), b.Base()) })
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		fset.AddFile(file.Name(), file.Base(), file.Size()).SetLines(file.Lines())
+	}
+	return fset
+}
+
+// parseFile implements packages.Config.ParseFile, reusing the syntax tree of
+// a file whose contents have not changed since it was last parsed.
+func (w *watcher) parseFile(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	w.mu.Lock()
+	cached, ok := w.parsed[filename]
+	w.mu.Unlock()
+	if ok && bytes.Equal(cached.src, src) {
+		return cached.file, cached.err
+	}
+
+	file, err := parser.ParseFile(fset, filename, src, parser.AllErrors|parser.ParseComments)
+	var tok *token.File
+	if file != nil {
+		tok = fset.File(file.FileStart)
+	}
+
+	w.mu.Lock()
+	w.parsed[filename] = parsedFile{src: bytes.Clone(src), file: file, tok: tok, err: err}
+	w.mu.Unlock()
+	return file, err
+}
+
+// formatDiagnostic renders a diagnostic on a single line in the style of
+// compiler output.
+func formatDiagnostic(d enumr.Diagnostic) string {
+	if d.Pos.Filename == "" {
+		return fmt.Sprintf("%s: %s [%s]", d.Severity, d.Message, d.Rule)
+	}
+	pos := d.Pos
+	pos.Filename = relativePath(pos.Filename)
+	return fmt.Sprintf("%s: %s: %s [%s]", pos, d.Severity, d.Message, d.Rule)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "card")
+	pkgDir := filepath.Join(dir, "card")
+	source := filepath.Join(pkgDir, "card.go")
+	content, err := os.ReadFile(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content = append(content, "\n// enumr:Low  Code:LO\n// enumr:High Code:HI\ntype Fee struct {\n\tCode string\n}\n"...)
+	if err = os.WriteFile(source, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(pkgDir)
+
+	targetTypes := []string{"Method", "Fee"}
+	opts := enumr.Options{MarshalField: "Code"}
+
+	// Leave per-type files behind, which the combined file replaces
+	split := opts
+	split.Split = true
+	var out bytes.Buffer
+	newWatcher(pkgDir).generate(t.Context(), &out, targetTypes, "", split)
+	stale := []string{filepath.Join(pkgDir, "method_enum.go"), filepath.Join(pkgDir, "fee_enum.go")}
+	for _, file := range stale {
+		if _, err = os.Stat(file); err != nil {
+			t.Fatalf("split generation did not write %s: %v\n%s", file, err, &out)
+		}
+	}
+
+	w := newWatcher(pkgDir)
+	seen, ran, err := w.poll(t.Context(), &out, nil, targetTypes, "", opts)
+	if err != nil || !ran {
+		t.Fatalf("first poll() = %v, %v; want a run", ran, err)
+	}
+	combined := filepath.Join(pkgDir, "card_enum.go")
+	if _, err = os.Stat(combined); err != nil {
+		t.Fatalf("poll() did not write %s: %v\n%s", combined, err, &out)
+	}
+	for _, file := range stale {
+		if _, err = os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("poll() did not remove stale %s", file)
+		}
+	}
+	if _, ok := seen[source]; !ok || len(seen) != 1 {
+		t.Errorf("poll() stamps = %v; want only the source %s", seen, source)
+	}
+
+	// Neither the written nor the removed files count as changes
+	if _, ran, err = w.poll(t.Context(), &out, seen, targetTypes, "", opts); err != nil || ran {
+		t.Errorf("poll() after generating = %v, %v; want no run", ran, err)
+	}
+
+	content = bytes.Replace(content, []byte("// enumr:Cash Code:CA\n"), []byte("// enumr:Cash Code:CA\n// enumr:Cheque Code:CH\n"), 1)
+	if err = os.WriteFile(source, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ran, err = w.poll(t.Context(), &out, seen, targetTypes, "", opts); err != nil || !ran {
+		t.Fatalf("poll() after editing card.go = %v, %v; want a run", ran, err)
+	}
+	generated, err := os.ReadFile(combined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(generated), `case "CH":`) {
+		t.Errorf("regenerated %s does not parse the new instance:\n%s", combined, generated)
+	}
+}
+
+func TestWatcherParseCache(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "card")
+	pkgDir := filepath.Join(dir, "card")
+	source := filepath.Join(pkgDir, "card.go")
+	fee := filepath.Join(pkgDir, "fee.go")
+	if err := os.WriteFile(fee, []byte("package card\n\n// Fee is charged per payment.\nconst Fee = 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(pkgDir)
+
+	var out bytes.Buffer
+	opts := enumr.Options{MarshalField: "Code"}
+	w := newWatcher(pkgDir)
+	w.generate(t.Context(), &out, []string{"Method"}, "", opts)
+	first := w.cfg.Fset
+	cached, ok := w.parsed[source]
+	if !ok {
+		t.Fatalf("card.go is not cached after generating:\n%s", &out)
+	}
+
+	renamed := filepath.Join(pkgDir, "fees.go")
+	if err := os.Rename(fee, renamed); err != nil {
+		t.Fatal(err)
+	}
+	w.generate(t.Context(), &out, []string{"Method"}, "", opts)
+
+	if w.cfg.Fset == first {
+		t.Error("generate() reused the file set of the previous run")
+	}
+	if _, ok = w.parsed[fee]; ok {
+		t.Error("the renamed fee.go is still cached")
+	}
+	if _, ok = w.parsed[renamed]; !ok {
+		t.Error("fees.go is not cached")
+	}
+	// The unchanged file is not parsed again, and its positions are still valid
+	if w.parsed[source].file != cached.file {
+		t.Error("card.go was parsed again although it did not change")
+	}
+	if pos := w.cfg.Fset.Position(cached.file.Package); pos.Filename != source || pos.Line != 1 {
+		t.Errorf("position of the cached card.go = %v; want %s:1", pos, source)
+	}
+	if _, err := os.Stat(filepath.Join(pkgDir, "method_enum.go")); err != nil {
+		t.Errorf("generate() did not write method_enum.go: %v\n%s", err, &out)
+	}
+}
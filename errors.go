@@ -0,0 +1,97 @@
+// Package enumr provides the runtime support used by code generated by the
+// enumr command. See github.com/jmfrees/go-enumr/cmd/enumr.
+package enumr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnknownValue is matched by errors.Is for every *UnknownValueError.
+var ErrUnknownValue = errors.New("unknown enum value")
+
+// ErrNoHandler is returned by generated Match functions, and wrapped by the
+// panics of MustBeExhaustive methods, when a value has no handler.
+var ErrNoHandler = errors.New("no handler")
+
+// UnknownValueError is returned by generated Parse functions and
+// UnmarshalText methods when the text is not a value of the enum.
+type UnknownValueError struct {
+	// Type is the name of the enum type.
+	Type string
+	// Value is the text that was parsed.
+	Value string
+	// Valid are the string representations of the enum's values.
+	Valid []string
+	// Suggestion is the valid value closest to Value, or empty if none is
+	// close enough to be a likely typo.
+	Suggestion string
+}
+
+// NewUnknownValueError returns an error for a value of the named type that is
+// not one of valid, suggesting the closest valid value.
+func NewUnknownValueError(typeName, value string, valid []string) *UnknownValueError {
+	return &UnknownValueError{
+		Type:       typeName,
+		Value:      value,
+		Valid:      valid,
+		Suggestion: Suggest(value, valid),
+	}
+}
+
+// Error implements the error interface.
+func (e *UnknownValueError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("unknown %s value %q", e.Type, e.Value)
+	}
+	return fmt.Sprintf("unknown %s value %q; did you mean %q?", e.Type, e.Value, e.Suggestion)
+}
+
+// Unwrap returns ErrUnknownValue.
+func (e *UnknownValueError) Unwrap() error {
+	return ErrUnknownValue
+}
+
+// Suggest returns the candidate closest to value by case-insensitive edit
+// distance, or the empty string if no candidate is close enough to be a
+// likely typo of value. Ties are resolved in favor of the earlier candidate.
+func Suggest(value string, candidates []string) string {
+	value = strings.ToLower(value)
+
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		distance := editDistance(value, strings.ToLower(candidate))
+		// Allow roughly one edit for every three characters of the candidate
+		if distance > max(1, utf8.RuneCountInString(candidate)/3) {
+			continue
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
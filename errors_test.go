@@ -0,0 +1,74 @@
+package enumr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"credit_card", "credit_card", 0},
+		{"credit card", "credit_card", 1},
+		{"credti_card", "credit_card", 2},
+		{"kitten", "sitting", 3},
+		{"žluť", "zluť", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	valid := []string{"credit_card", "pay_pal", "bank_transfer"}
+
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"credit card", "credit_card"},
+		{"Credit_Card", "credit_card"},
+		{"paypal", "pay_pal"},
+		{"bank", ""},
+		{"x", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Suggest(tt.value, valid); got != tt.expected {
+			t.Errorf("Suggest(%q) = %q; want %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestUnknownValueError(t *testing.T) {
+	var err error = NewUnknownValueError("Method", "paypall", []string{"credit_card", "pay_pal"})
+
+	if !errors.Is(err, ErrUnknownValue) {
+		t.Errorf("errors.Is(%v, ErrUnknownValue) = false; want true", err)
+	}
+
+	var unknown *UnknownValueError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("errors.As(%v, *UnknownValueError) = false; want true", err)
+	}
+	if unknown.Suggestion != "pay_pal" {
+		t.Errorf("Suggestion = %q; want %q", unknown.Suggestion, "pay_pal")
+	}
+
+	want := `unknown Method value "paypall"; did you mean "pay_pal"?`
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+
+	want = `unknown Method value "cash"`
+	if got := NewUnknownValueError("Method", "cash", []string{"pay_pal"}).Error(); got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+}
@@ -2,9 +2,12 @@ module github.com/jmfrees/go-enumr
 
 go 1.24
 
-require golang.org/x/tools v0.30.0
-
 require (
-	golang.org/x/mod v0.23.0 // indirect
-	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/mod v0.23.0
+	// golang.org/x/text provides the Unicode normalization of the nfc and
+	// nfkc parse modes, both when resolving values and in generated code.
+	golang.org/x/text v0.22.0
+	golang.org/x/tools v0.30.0
 )
+
+require golang.org/x/sync v0.11.0 // indirect
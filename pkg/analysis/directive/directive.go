@@ -0,0 +1,112 @@
+// Package directive provides an analyzer that reports malformed enumr
+// directives, so that editors show them as they are typed rather than when
+// enumr runs.
+package directive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// Analyzer reports the problems enumr would warn about in the directives of
+// type declarations and in the attribute lines of vars of struct types
+// declared in the package, which declare instances in manual mode, with
+// suggested fixes for misspelled field and attribute names.
+var Analyzer = &analysis.Analyzer{
+	Name: "enumrdirective",
+	Doc:  "check that enumr directives are well-formed and set existing fields and attributes",
+	URL:  "https://github.com/jmfrees/go-enumr",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue
+		}
+		tokFile := pass.Fset.File(file.Pos())
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch genDecl.Tok {
+			case token.TYPE:
+				for _, spec := range genDecl.Specs {
+					spec := spec.(*ast.TypeSpec)
+					doc := genDecl.Doc
+					if spec.Doc != nil {
+						doc = spec.Doc
+					}
+					for _, diag := range enumr.CheckDirectives(pass.Fset, pass.TypesInfo, spec, doc) {
+						pass.Report(diagnostic(tokFile, diag))
+					}
+				}
+			case token.VAR:
+				for _, spec := range genDecl.Specs {
+					spec := spec.(*ast.ValueSpec)
+					// Like enumr, read the doc of a single var declaration
+					// from the declaration if the spec has none
+					doc := spec.Doc
+					if doc == nil && len(genDecl.Specs) == 1 {
+						doc = genDecl.Doc
+					}
+					if doc == nil || !declaresInstance(pass, spec) {
+						continue
+					}
+					for _, diag := range enumr.CheckInstanceDirectives(pass.Fset, doc) {
+						pass.Report(diagnostic(tokFile, diag))
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// declaresInstance reports whether spec declares a var of a struct type of
+// the package being analyzed, which enumr takes for an instance in manual
+// mode.
+func declaresInstance(pass *analysis.Pass, spec *ast.ValueSpec) bool {
+	for _, name := range spec.Names {
+		v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+		if !ok {
+			continue
+		}
+		named, ok := v.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != pass.Pkg {
+			continue
+		}
+		if _, isStruct := named.Underlying().(*types.Struct); isStruct {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnostic converts an enumr diagnostic in the given file.
+func diagnostic(tokFile *token.File, diag enumr.Diagnostic) analysis.Diagnostic {
+	pos := tokFile.Pos(diag.Pos.Offset)
+	report := analysis.Diagnostic{
+		Pos:      pos,
+		Category: diag.Rule,
+		Message:  diag.Message,
+	}
+	if diag.Fix != nil {
+		report.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Replace %s with %s", diag.Fix.Old, diag.Fix.New),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     pos,
+				End:     pos + token.Pos(len(diag.Fix.Old)),
+				NewText: []byte(diag.Fix.New),
+			}},
+		}}
+	}
+	return report
+}
@@ -0,0 +1,64 @@
+package payment
+
+// Bonus is the fee of bank transfers.
+const Bonus = 3
+
+// want +1 `unknown attribute "@pars"; did you mean "@parse"\?`
+// enumr:@pars=fold
+// want +1 `directive for Card sets unknown field "Cod"; did you mean "Code"\?`
+// enumr:Card   Cod:CC
+// want +1 `directive for Cash sets field Fee to 1.5, which is not a valid int`
+// enumr:Cash   Code:CA Fee:1.5
+// want +1 `directive has an unterminated quote`
+// enumr:Cheque Code:"CH Fee:2
+// want +1 `directive for Gift sets field Fee to 1x, which is not a Go expression`
+// enumr:Gift   Code:GC Fee:1x
+// want +1 `directive for Wire sets field Active to 1, which is not a valid bool`
+// enumr:Wire   Code:WI Active:1
+// want +1 `directive for Free sets unknown field "Zilch"$`
+// enumr:Free   Code:FR Fee:-0 Zilch:1
+// enumr:Bank   Code:BA Fee:Bonus Active:true
+// want +1 `directive for Token sets field Fee to "2", which is not a valid int`
+// enumr:Token  Code:TK Fee:"2"
+// want +1 `directive for Promo sets field Fee to Bonsu, which is undefined`
+// enumr:Promo  Code:PR Fee:Bonsu
+// want +1 `directive for Coupon sets unknown field "ode"; did you mean "Code"\?`
+// enumr:Coupon Code:CP ode:CP
+type Method struct {
+	Code   string
+	Fee    int
+	Active bool
+}
+
+// want +1 `unknown parse mode "foldd"`
+// enumr:@parse=foldd
+// enumr:Red
+// enumr:Blue
+type Color struct{}
+
+// Plain has no directives.
+type Plain struct {
+	Code string
+}
+
+// Level declares its instances in manual mode.
+type Level struct {
+	Name string
+}
+
+// want +1 `unknown attribute "@alais"$`
+// enumr:@alais=lo
+var Low = Level{Name: "low"}
+
+var (
+	// enumr:@alias=hi
+	High = Level{Name: "high"}
+	// want +1 `unknown attribute "@deprecatd"; did you mean "@deprecated"\?`
+	// enumr:@deprecatd="use High"
+	Top = Level{Name: "top"}
+)
+
+// Limit is not an instance, so its directives are not checked.
+//
+// enumr:@alais=max
+var Limit = 10
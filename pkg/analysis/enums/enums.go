@@ -0,0 +1,221 @@
+// Package enums provides an analyzer that finds the types enumr generated code
+// for, so that other analyzers can check how they are used.
+//
+// A type is recognized by the <Type>Values function in a file generated by
+// enumr, and its instances by the cases of its generated String method, which
+// also lists deprecated instances that <Type>Values leaves out. The analyzer
+// exports a Fact for each such type, so that the types of imported packages
+// are recognized as well.
+package enums
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/jmfrees/go-enumr/pkg/enumr"
+)
+
+// Analyzer finds the types with enumr-generated code. Its result is an
+// *Enums covering the analyzed package and its dependencies.
+var Analyzer = &analysis.Analyzer{
+	Name:       "enums",
+	Doc:        "find types with code generated by enumr",
+	URL:        "https://github.com/jmfrees/go-enumr",
+	Run:        run,
+	FactTypes:  []analysis.Fact{new(Fact)},
+	ResultType: reflect.TypeOf(new(Enums)),
+}
+
+// Fact records that enumr generated code for a type.
+type Fact struct {
+	// Instances are the names of the package-level vars returned by
+	// <Type>Values, in order.
+	Instances []string
+	// Deprecated are the names of the instances that <Type>Values leaves out
+	// because they are deprecated, in order.
+	Deprecated []string
+}
+
+// AFact implements analysis.Fact.
+func (*Fact) AFact() {}
+
+// String returns the instances of the enum, for analysistest.
+func (f *Fact) String() string {
+	if len(f.Deprecated) > 0 {
+		return "enum(" + strings.Join(f.Instances, ", ") + "; deprecated " + strings.Join(f.Deprecated, ", ") + ")"
+	}
+	return "enum(" + strings.Join(f.Instances, ", ") + ")"
+}
+
+// Enum is a type with enumr-generated code.
+type Enum struct {
+	Type *types.TypeName
+	// Instances are the vars returned by <Type>Values, in order.
+	Instances []*types.Var
+	// Deprecated are the instances left out of <Type>Values, in order.
+	Deprecated []*types.Var
+}
+
+// Name returns the name of the enum type as seen from pkg, qualified by
+// package name if it is declared in another package.
+func (e *Enum) Name(pkg *types.Package) string {
+	if e.Type.Pkg() == pkg {
+		return e.Type.Name()
+	}
+	return e.Type.Pkg().Name() + "." + e.Type.Name()
+}
+
+// Enums holds the enums of a package and its dependencies.
+type Enums struct {
+	byType     map[*types.TypeName]*Enum
+	byInstance map[*types.Var]*Enum
+}
+
+// Of returns the enum of the given type, if it is one. Pointer types are not
+// enums.
+func (e *Enums) Of(t types.Type) (*Enum, bool) {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil, false
+	}
+	enum, ok := e.byType[named.Obj()]
+	return enum, ok
+}
+
+// Instance returns the enum that v is an instance of, if any.
+func (e *Enums) Instance(v *types.Var) (*Enum, bool) {
+	enum, ok := e.byInstance[v]
+	return enum, ok
+}
+
+func (e *Enums) add(typeName *types.TypeName, fact *Fact) {
+	enum := &Enum{Type: typeName}
+	lookup := func(names []string) []*types.Var {
+		var vars []*types.Var
+		for _, name := range names {
+			if instance, ok := typeName.Pkg().Scope().Lookup(name).(*types.Var); ok {
+				vars = append(vars, instance)
+				e.byInstance[instance] = enum
+			}
+		}
+		return vars
+	}
+	enum.Instances = lookup(fact.Instances)
+	enum.Deprecated = lookup(fact.Deprecated)
+	e.byType[typeName] = enum
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	enums := &Enums{
+		byType:     make(map[*types.TypeName]*Enum),
+		byInstance: make(map[*types.Var]*Enum),
+	}
+
+	facts := make(map[*types.TypeName]*Fact)
+	cases := make(map[*types.TypeName][]string)
+	for _, file := range pass.Files {
+		if !enumr.IsGeneratedFile(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if typeName, fact, ok := valuesFunc(pass, decl); ok {
+				facts[typeName] = fact
+			}
+			if typeName, names, ok := stringMethod(pass, decl); ok {
+				cases[typeName] = names
+			}
+		}
+	}
+	for typeName, fact := range facts {
+		for _, name := range cases[typeName] {
+			if !slices.Contains(fact.Instances, name) {
+				fact.Deprecated = append(fact.Deprecated, name)
+			}
+		}
+		pass.ExportObjectFact(typeName, fact)
+	}
+
+	for _, objectFact := range pass.AllObjectFacts() {
+		typeName, isType := objectFact.Object.(*types.TypeName)
+		fact, isEnum := objectFact.Fact.(*Fact)
+		if isType && isEnum {
+			enums.add(typeName, fact)
+		}
+	}
+	return enums, nil
+}
+
+// valuesFunc recognizes a generated <Type>Values function, returning the type
+// and the fact describing its instances.
+func valuesFunc(pass *analysis.Pass, decl ast.Decl) (*types.TypeName, *Fact, bool) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Recv != nil || fn.Body == nil || !strings.HasSuffix(fn.Name.Name, "Values") {
+		return nil, nil, false
+	}
+	typeName, ok := pass.Pkg.Scope().Lookup(strings.TrimSuffix(fn.Name.Name, "Values")).(*types.TypeName)
+	if !ok || len(fn.Body.List) != 1 {
+		return nil, nil, false
+	}
+
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil, nil, false
+	}
+	lit, ok := ret.Results[0].(*ast.CompositeLit)
+	if !ok {
+		return nil, nil, false
+	}
+
+	fact := new(Fact)
+	for _, elt := range lit.Elts {
+		ident, ok := elt.(*ast.Ident)
+		if !ok {
+			return nil, nil, false
+		}
+		if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok && v.Pkg() == pass.Pkg {
+			fact.Instances = append(fact.Instances, v.Name())
+		}
+	}
+	return typeName, fact, true
+}
+
+// stringMethod recognizes a generated String method, returning its receiver
+// type and the names of the instances its switch has cases for, in order.
+func stringMethod(pass *analysis.Pass, decl ast.Decl) (*types.TypeName, []string, bool) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Recv == nil || fn.Name.Name != "String" || fn.Body == nil || len(fn.Body.List) == 0 {
+		return nil, nil, false
+	}
+	recv, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return nil, nil, false
+	}
+	named, ok := recv.Signature().Recv().Type().(*types.Named)
+	if !ok {
+		return nil, nil, false
+	}
+	stmt, ok := fn.Body.List[0].(*ast.SwitchStmt)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var names []string
+	for _, clause := range stmt.Body.List {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			ident, ok := expr.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+			if ok && v.Pkg() == pass.Pkg && types.Identical(v.Type(), named) {
+				names = append(names, v.Name())
+			}
+		}
+	}
+	return named.Obj(), names, true
+}
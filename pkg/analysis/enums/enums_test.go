@@ -0,0 +1,47 @@
+package enums
+
+import (
+	"go/types"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// The analyzers share the testdata in the parent directory, whose enum code is
+// generated by enumr.
+//go:generate ../../../enumr -type=Method -marshal-field=Code -exclude-deprecated ../testdata/src/payment
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, testdata, Analyzer, "catalog")
+
+	pkg := results[0].Pass.Pkg
+	payment := pkg.Imports()[0]
+	enums := results[0].Result.(*Enums)
+	method := payment.Scope().Lookup("Method")
+	enum, ok := enums.Of(method.Type())
+	if !ok || len(enum.Instances) != 3 || enum.Instances[2].Name() != "Cheque" {
+		t.Errorf("Of(Method) = %+v, %v; want the enum with Card, Cash and Cheque", enum, ok)
+	}
+	if len(enum.Deprecated) != 1 || enum.Deprecated[0].Name() != "Voucher" {
+		t.Errorf("Of(Method).Deprecated = %v; want Voucher, which MethodValues leaves out", enum.Deprecated)
+	}
+	for _, instance := range []*types.Var{enum.Instances[0], enum.Deprecated[0]} {
+		if _, ok := enums.Instance(instance); !ok {
+			t.Errorf("Instance(%s) reports no enum", instance.Name())
+		}
+	}
+	if got := enum.Name(pkg); got != "payment.Method" {
+		t.Errorf("Name() = %q; want %q", got, "payment.Method")
+	}
+	if got := enum.Name(payment); got != "Method" {
+		t.Errorf("Name() in the declaring package = %q; want %q", got, "Method")
+	}
+	if _, ok := enums.Of(payment.Scope().Lookup("Plain").Type()); ok {
+		t.Errorf("Of(Plain) reports an enum without generated code")
+	}
+}
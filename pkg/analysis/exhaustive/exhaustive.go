@@ -0,0 +1,93 @@
+// Package exhaustive provides an analyzer that reports switch statements over
+// enumr enums that do not handle every value.
+package exhaustive
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/jmfrees/go-enumr/pkg/analysis/enums"
+)
+
+// Analyzer reports switch statements over a type with enumr-generated code
+// that have neither a case for every instance returned by <Type>Values nor a
+// default case.
+var Analyzer = &analysis.Analyzer{
+	Name:     "enumrexhaustive",
+	Doc:      "check that switch statements over enumr enums handle every value",
+	URL:      "https://github.com/jmfrees/go-enumr",
+	Run:      run,
+	Requires: []*analysis.Analyzer{enums.Analyzer},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	known := pass.ResultOf[enums.Analyzer].(*enums.Enums)
+
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			stmt, ok := node.(*ast.SwitchStmt)
+			if !ok || stmt.Tag == nil {
+				return true
+			}
+			enum, ok := known.Of(pass.TypesInfo.TypeOf(stmt.Tag))
+			if !ok {
+				return true
+			}
+			if missing := missingInstances(pass, stmt, enum); len(missing) > 0 {
+				pass.Reportf(
+					stmt.Pos(),
+					"missing cases in switch of type %s: %s",
+					enum.Name(pass.Pkg),
+					strings.Join(missing, ", "),
+				)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// missingInstances returns the names of the instances of enum that no case of
+// the switch handles, or nil if the switch has a default case.
+func missingInstances(pass *analysis.Pass, stmt *ast.SwitchStmt, enum *enums.Enum) []string {
+	handled := make(map[*types.Var]bool)
+	for _, clause := range stmt.Body.List {
+		clause := clause.(*ast.CaseClause)
+		if clause.List == nil {
+			return nil
+		}
+		for _, expr := range clause.List {
+			if v, ok := referencedVar(pass, expr); ok {
+				handled[v] = true
+			}
+		}
+	}
+
+	var missing []string
+	for _, instance := range enum.Instances {
+		if !handled[instance] {
+			missing = append(missing, instance.Name())
+		}
+	}
+	return missing
+}
+
+// referencedVar returns the variable an identifier or qualified identifier
+// refers to.
+func referencedVar(pass *analysis.Pass, expr ast.Expr) (*types.Var, bool) {
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		v, ok := pass.TypesInfo.Uses[expr].(*types.Var)
+		return v, ok
+	case *ast.SelectorExpr:
+		v, ok := pass.TypesInfo.Uses[expr.Sel].(*types.Var)
+		return v, ok
+	}
+	return nil, false
+}
@@ -0,0 +1,171 @@
+// Package immutable provides an analyzer that reports code changing the
+// instances of enumr enums or constructing values that are not instances.
+//
+// Generated String, Parse and Values functions identify values by comparing
+// them with the instance vars, so changing an instance, or building a value
+// with a composite literal, produces values that no longer match.
+package immutable
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/jmfrees/go-enumr/pkg/analysis/enums"
+)
+
+// Analyzer reports assignments to enum instance vars or their fields, calls
+// of pointer methods on instances, and, outside the package that declares
+// them, taking the address of instances and non-empty composite literals of
+// enum types.
+var Analyzer = &analysis.Analyzer{
+	Name:     "enumrimmutable",
+	Doc:      "check that enumr enum instances are not changed and values are not built from literals",
+	URL:      "https://github.com/jmfrees/go-enumr",
+	Run:      run,
+	Requires: []*analysis.Analyzer{enums.Analyzer},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	known := pass.ResultOf[enums.Analyzer].(*enums.Enums)
+
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				if node.Tok == token.DEFINE {
+					break
+				}
+				for _, lhs := range node.Lhs {
+					checkMutation(pass, known, lhs, "assignment to")
+				}
+			case *ast.IncDecStmt:
+				checkMutation(pass, known, node.X, "assignment to")
+			case *ast.CallExpr:
+				checkPointerMethod(pass, known, node)
+			case *ast.UnaryExpr:
+				if node.Op == token.AND {
+					checkAddress(pass, known, node)
+				}
+			case *ast.CompositeLit:
+				checkLiteral(pass, known, node)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// checkMutation reports expr if it is an enum instance or a part of one.
+func checkMutation(pass *analysis.Pass, known *enums.Enums, expr ast.Expr, action string) {
+	instance, whole := instanceOf(pass, expr)
+	if instance == nil {
+		return
+	}
+	if _, ok := known.Instance(instance); !ok {
+		return
+	}
+	target := "field of enum instance"
+	if whole {
+		target = "enum instance"
+	}
+	pass.Reportf(expr.Pos(), "%s %s %s", action, target, instanceName(pass, instance))
+}
+
+// checkPointerMethod reports calls of pointer methods, such as UnmarshalText,
+// on an enum instance.
+func checkPointerMethod(pass *analysis.Pass, known *enums.Enums, call *ast.CallExpr) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return
+	}
+	signature := selection.Obj().Type().(*types.Signature)
+	if _, isPointer := signature.Recv().Type().(*types.Pointer); !isPointer {
+		return
+	}
+	if _, isPointer := types.Unalias(selection.Recv()).(*types.Pointer); isPointer {
+		return
+	}
+	checkMutation(pass, known, sel.X, "call of pointer method "+sel.Sel.Name+" on")
+}
+
+// checkAddress reports taking the address of an enum instance or a part of
+// one outside the package that declares it, as the pointer can change the
+// instance.
+func checkAddress(pass *analysis.Pass, known *enums.Enums, unary *ast.UnaryExpr) {
+	if instance, _ := instanceOf(pass, unary.X); instance == nil || instance.Pkg() == pass.Pkg {
+		return
+	}
+	checkMutation(pass, known, unary.X, "taking the address of")
+}
+
+// checkLiteral reports non-empty composite literals of enum types outside the
+// package that declares them.
+func checkLiteral(pass *analysis.Pass, known *enums.Enums, lit *ast.CompositeLit) {
+	enum, ok := known.Of(pass.TypesInfo.TypeOf(lit))
+	if !ok || enum.Type.Pkg() == pass.Pkg || len(lit.Elts) == 0 {
+		return
+	}
+	pass.Reportf(
+		lit.Pos(),
+		"composite literal of enum type %s outside package %s; use one of its values instead",
+		enum.Name(pass.Pkg),
+		enum.Type.Pkg().Name(),
+	)
+}
+
+// instanceOf returns the package-level var that expr is or is a part of, via
+// field selections and array indexing, and whether expr is the whole var.
+func instanceOf(pass *analysis.Pass, expr ast.Expr) (*types.Var, bool) {
+	whole := true
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			v, _ := pass.TypesInfo.Uses[e].(*types.Var)
+			return packageVar(v), whole
+		case *ast.SelectorExpr:
+			if selection, ok := pass.TypesInfo.Selections[e]; ok {
+				if selection.Kind() != types.FieldVal || selection.Indirect() {
+					return nil, false
+				}
+				expr, whole = e.X, false
+				continue
+			}
+			v, _ := pass.TypesInfo.Uses[e.Sel].(*types.Var)
+			return packageVar(v), whole
+		case *ast.IndexExpr:
+			if _, isArray := types.Unalias(pass.TypesInfo.TypeOf(e.X)).Underlying().(*types.Array); !isArray {
+				return nil, false
+			}
+			expr, whole = e.X, false
+		default:
+			return nil, false
+		}
+	}
+}
+
+// packageVar returns v if it is a package-level var.
+func packageVar(v *types.Var) *types.Var {
+	if v == nil || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return nil
+	}
+	return v
+}
+
+// instanceName returns the name of an instance as seen from the analyzed
+// package.
+func instanceName(pass *analysis.Pass, instance *types.Var) string {
+	if instance.Pkg() == pass.Pkg {
+		return instance.Name()
+	}
+	return instance.Pkg().Name() + "." + instance.Name()
+}
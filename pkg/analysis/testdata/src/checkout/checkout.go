@@ -0,0 +1,36 @@
+package checkout
+
+import "payment"
+
+func fee(m payment.Method) int {
+	switch m { // want `missing cases in switch of type payment.Method: Cash, Cheque`
+	case payment.Card:
+		return 3
+	}
+	return 0
+}
+
+func label(m payment.Method) string {
+	switch m {
+	case payment.Card, payment.Cash, payment.Cheque:
+		return "known"
+	}
+	return ""
+}
+
+func labelWithDefault(m payment.Method) string {
+	switch m {
+	case payment.Card:
+		return "card"
+	default:
+		return "other"
+	}
+}
+
+func notAnEnum(s string) int {
+	switch s {
+	case "a":
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,30 @@
+package payment
+
+// enumr:Card    Code:CC
+// enumr:Cash    Code:CA
+// enumr:Cheque  Code:CH
+// enumr:Voucher Code:VO @deprecated="use Card"
+type Method struct {
+	Code  string
+	Flags [2]bool
+}
+
+// Custom builds values in the declaring package, which is allowed.
+var Custom = Method{Code: "XX"}
+
+// Default points to an instance in the declaring package, which is allowed.
+var Default = &Card
+
+func init() {
+	Cheque.Code = "CQ" // want `assignment to field of enum instance Cheque`
+}
+
+// Plain has no generated code.
+type Plain struct {
+	Code string
+}
+
+// PlainValues is not generated by enumr.
+func PlainValues() []Plain {
+	return []Plain{{Code: "x"}}
+}
@@ -0,0 +1,109 @@
+// Code generated by enumr. DO NOT EDIT.
+// enumr-hash: 4075ce28e99c6531b47422f488ce7f035d349df7a9f5f4c5039b63213f45183e
+
+package payment
+
+import (
+	"fmt"
+)
+
+var (
+	Card   = Method{Code: "CC"}
+	Cash   = Method{Code: "CA"}
+	Cheque = Method{Code: "CH"}
+	// Deprecated: use Card
+	Voucher = Method{Code: "VO"}
+)
+
+// String converts the enum value to its corresponding marshal field.
+func (t Method) String() string {
+	switch t {
+	case Card:
+		return "CC"
+	case Cash:
+		return "CA"
+	case Cheque:
+		return "CH"
+	case Voucher:
+		return "VO"
+	}
+	return ""
+}
+
+// MethodDeprecatedHook, if set, is called by ParseMethod with the
+// value and the parsed text whenever a deprecated value is parsed, e.g. to
+// measure its remaining use. Set it during initialization; it must not be
+// changed while values are being parsed.
+var MethodDeprecatedHook func(value Method, text string)
+
+// ParseMethod converts a string to a Method.
+func ParseMethod(text string) (Method, error) {
+	switch text {
+	case "CC":
+		return Card, nil
+	case "CA":
+		return Cash, nil
+	case "CH":
+		return Cheque, nil
+	case "VO":
+		if MethodDeprecatedHook != nil {
+			MethodDeprecatedHook(Voucher, text)
+		}
+		return Voucher, nil
+	default:
+		return Method{}, fmt.Errorf("unknown Method value %q", text)
+	}
+}
+
+// MarshalText converts the enum value to a string.
+func (t Method) MarshalText() (text []byte, err error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText converts a string to the appropriate enum value.
+func (t *Method) UnmarshalText(text []byte) error {
+	val, err := ParseMethod(string(text))
+	if err != nil {
+		return err
+	}
+	*t = val
+	return nil
+}
+
+// IsZero reports whether t is the zero value.
+func (t Method) IsZero() bool {
+	return t == Method{}
+}
+
+// IsValid reports whether t is one of the declared values.
+func (t Method) IsValid() bool {
+	switch t {
+	case Card, Cash, Cheque, Voucher:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if t is not valid. The error identifies t by its
+// string representation if it has one, or else by its fields.
+func (t Method) Validate() error {
+	if t.IsValid() {
+		return nil
+	}
+	value := t.String()
+	if value == "" {
+		// Format the fields without calling String again
+		type fields Method
+		value = fmt.Sprintf("%+v", fields(t))
+	}
+	return fmt.Errorf("unknown Method value %q", value)
+}
+
+// MethodValues returns all values of the enum that are not deprecated.
+func MethodValues() []Method {
+	return []Method{
+		Card,
+		Cash,
+		Cheque,
+	}
+}
@@ -0,0 +1,36 @@
+package shop
+
+import "payment"
+
+var preferred = payment.Card
+
+func mutate() {
+	payment.Card.Code = "X"      // want `assignment to field of enum instance payment.Card`
+	payment.Cash = payment.Card  // want `assignment to enum instance payment.Cash`
+	payment.Card.Flags[0] = true // want `assignment to field of enum instance payment.Card`
+	(payment.Cash).Code += "Y"   // want `assignment to field of enum instance payment.Cash`
+
+	payment.Voucher.Code = "V" // want `assignment to field of enum instance payment.Voucher`
+	_ = &payment.Voucher       // want `taking the address of enum instance payment.Voucher`
+
+	_ = payment.Cheque.UnmarshalText([]byte("CC")) // want `call of pointer method UnmarshalText on enum instance payment.Cheque`
+
+	preferred = payment.Cash
+	preferred.Code = "Z"
+	local := payment.Card
+	local.Code = "Z"
+	_ = local.UnmarshalText(nil)
+
+	m := &payment.Card // want `taking the address of enum instance payment.Card`
+	m.Code = "Z"
+	_ = &payment.Cash.Flags // want `taking the address of field of enum instance payment.Cash`
+	_ = &preferred
+}
+
+func build() []payment.Method {
+	return []payment.Method{
+		payment.Method{Code: "CC"}, // want `composite literal of enum type payment.Method outside package payment; use one of its values instead`
+		{Code: "CA"},               // want `composite literal of enum type payment.Method outside package payment`
+		payment.Method{},
+	}
+}
@@ -0,0 +1,106 @@
+package enumr
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// resolveAliases sets the Aliases of each instance from its @alias attribute.
+// No two instances may have string representations that Parse cannot tell
+// apart, and an alias may not repeat the string representation or an alias of
+// any instance of the type. Strings are compared after the normalization and
+// case folding of the type's parse mode.
+func (e *enumInfo) resolveAliases() error {
+	var errs []error
+	owners := make(map[string]string)
+	for _, instance := range e.Instances {
+		value := e.wireValue(instance)
+		if owner, taken := owners[e.parseKey(value)]; taken {
+			errs = append(errs, errorAt(
+				RuleDuplicateValue,
+				instance.Pos,
+				"value %q of %s cannot be parsed apart from the value of %s",
+				value,
+				instance.Name,
+				owner,
+			))
+			continue
+		}
+		owners[e.parseKey(value)] = instance.Name
+	}
+
+	for i := range e.Instances {
+		instance := &e.Instances[i]
+		attr, ok := instance.Attributes["alias"]
+		if !ok {
+			continue
+		}
+		for _, alias := range attr.list() {
+			if owner, taken := owners[e.parseKey(alias)]; taken {
+				errs = append(errs, errorAt(
+					RuleDuplicateAlias,
+					attr.Pos,
+					"alias %q of %s is already used by %s",
+					alias,
+					instance.Name,
+					owner,
+				))
+				continue
+			}
+			owners[e.parseKey(alias)] = instance.Name
+			instance.Aliases = append(instance.Aliases, alias)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ParseLabels returns the Go expressions Parse<Type> compares its input with
+// to match instance: its string representation followed by its aliases.
+// String literals are normalized like the input.
+func (e enumInfo) ParseLabels(instance instanceData) []string {
+	value := strconv.Quote(e.normalize(transformName(e.CaseFormat)(instance.Name)))
+	if e.MarshalField != "" {
+		value = instance.Fields[e.MarshalField]
+		if s, err := strconv.Unquote(value); err == nil {
+			value = strconv.Quote(e.normalize(s))
+		}
+	}
+	labels := []string{value}
+	for _, alias := range instance.Aliases {
+		labels = append(labels, strconv.Quote(e.normalize(alias)))
+	}
+	return labels
+}
+
+// normalize applies the Unicode normalization of the parse mode to s.
+func (e enumInfo) normalize(s string) string {
+	switch e.ParseMode.Normalize {
+	case "NFC":
+		return norm.NFC.String(s)
+	case "NFKC":
+		return norm.NFKC.String(s)
+	}
+	return s
+}
+
+// parseKey returns a key that is equal for two strings exactly when Parse
+// matches them as the same input.
+func (e enumInfo) parseKey(s string) string {
+	s = e.normalize(s)
+	if !e.ParseMode.Fold {
+		return s
+	}
+	// Map each rune to the smallest rune of its simple case folding orbit,
+	// which is what strings.EqualFold compares
+	return strings.Map(func(r rune) rune {
+		smallest := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			smallest = min(smallest, f)
+		}
+		return smallest
+	}, s)
+}
@@ -0,0 +1,135 @@
+package enumr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveAliases(t *testing.T) {
+	alias := func(value string) attributes {
+		return attributes{"alias": {Value: value}}
+	}
+
+	tests := []struct {
+		name        string
+		enum        enumInfo
+		wantAliases [][]string
+		wantErrs    int
+	}{
+		{
+			name: "Aliases",
+			enum: enumInfo{
+				CaseFormat: "snake_case",
+				Instances: []instanceData{
+					{Name: "CreditCard", Attributes: alias("card, cc_old")},
+					{Name: "PayPal"},
+				},
+			},
+			wantAliases: [][]string{{"card", "cc_old"}, nil},
+		},
+		{
+			name: "Alias of another instance's value",
+			enum: enumInfo{
+				CaseFormat: "snake_case",
+				Instances: []instanceData{
+					{Name: "CreditCard", Attributes: alias("pay_pal,card")},
+					{Name: "PayPal", Attributes: alias("card")},
+				},
+			},
+			wantAliases: [][]string{{"card"}, nil},
+			wantErrs:    2,
+		},
+		{
+			name: "Case-insensitive duplicate",
+			enum: enumInfo{
+				ParseMode: parseMode{Fold: true},
+				Instances: []instanceData{
+					{Name: "CreditCard", Attributes: alias("Card")},
+					{Name: "PayPal", Attributes: alias("CARD")},
+				},
+			},
+			wantAliases: [][]string{{"Card"}, nil},
+			wantErrs:    1,
+		},
+		{
+			name: "Values that differ in case",
+			enum: enumInfo{
+				MarshalField: "Code",
+				ParseMode:    parseMode{Fold: true},
+				Instances: []instanceData{
+					{Name: "Card", Fields: map[string]string{"Code": `"CC"`}},
+					{Name: "Cash", Fields: map[string]string{"Code": `"cc"`}},
+				},
+			},
+			wantAliases: [][]string{nil, nil},
+			wantErrs:    1,
+		},
+		{
+			name: "Simple case folding",
+			enum: enumInfo{
+				ParseMode: parseMode{Fold: true},
+				Instances: []instanceData{
+					{Name: "Kelvin", Attributes: alias("\u212a")},
+					{Name: "Other", Attributes: alias("k")},
+				},
+			},
+			wantAliases: [][]string{{"\u212a"}, nil},
+			wantErrs:    1,
+		},
+		{
+			name: "Aliases equal after normalization",
+			enum: enumInfo{
+				ParseMode: parseMode{Normalize: "NFC"},
+				Instances: []instanceData{
+					{Name: "Zurich", Attributes: alias("z\u00fcrich")},
+					{Name: "Other", Attributes: alias("zu\u0308rich")},
+				},
+			},
+			wantAliases: [][]string{{"z\u00fcrich"}, nil},
+			wantErrs:    1,
+		},
+		{
+			name: "Marshal field values",
+			enum: enumInfo{
+				MarshalField: "Code",
+				Instances: []instanceData{
+					{Name: "CreditCard", Fields: map[string]string{"Code": `"CC"`}, Attributes: alias("PP")},
+					{Name: "PayPal", Fields: map[string]string{"Code": `"PP"`}},
+				},
+			},
+			wantAliases: [][]string{nil, nil},
+			wantErrs:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.enum.resolveAliases()
+			if diags := Diagnostics(err); len(diags) != tt.wantErrs {
+				t.Errorf("resolveAliases() error = %v; want %d diagnostics", err, tt.wantErrs)
+			}
+			for i, instance := range tt.enum.Instances {
+				if !reflect.DeepEqual(instance.Aliases, tt.wantAliases[i]) {
+					t.Errorf("%s: Aliases = %q; want %q", instance.Name, instance.Aliases, tt.wantAliases[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	enum := enumInfo{
+		MarshalField: "Code",
+		ParseMode:    parseMode{Normalize: "NFC"},
+	}
+	instance := instanceData{
+		Name:    "Zurich",
+		Fields:  map[string]string{"Code": "`zu\u0308rich`"},
+		Aliases: []string{"zu\u0308ri"},
+	}
+
+	want := []string{`"zürich"`, `"züri"`}
+	if got := enum.ParseLabels(instance); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLabels() = %q; want %q", got, want)
+	}
+}
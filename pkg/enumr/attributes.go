@@ -0,0 +1,213 @@
+package enumr
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	goenumr "github.com/jmfrees/go-enumr"
+)
+
+// attribute is a setting given as an "@key=value" directive argument, as
+// opposed to a "Key:Value" argument that sets a struct field.
+type attribute struct {
+	Value string
+	Pos   token.Position
+}
+
+// attributes maps attribute keys, without the "@", to their settings.
+type attributes map[string]attribute
+
+// typeAttributes are the attributes that may be set on a type with a
+// "//enumr:@key=value" line in its doc comment.
+var typeAttributes = []string{"ids", "parse", "unknown", "wrap"}
+
+// instanceAttributes are the attributes that may be set on an instance, as
+// arguments of its directive or with a "//enumr:@key=value" line in the doc
+// comment of its var.
+var instanceAttributes = []string{"alias", "default", "deprecated", "id", "order"}
+
+// parseTypeAttributes parses the attribute lines of a type's doc comment.
+// Lines that declare instances are ignored.
+func parseTypeAttributes(
+	ctx context.Context,
+	logger *slog.Logger,
+	fset *token.FileSet,
+	doc *ast.CommentGroup,
+) attributes {
+	return parseAttributeLines(ctx, logger, fset, doc, typeAttributes)
+}
+
+// parseAttributeLines parses the "//enumr:@key=value" lines of a doc comment,
+// warning about attributes that are not in known.
+func parseAttributeLines(
+	ctx context.Context,
+	logger *slog.Logger,
+	fset *token.FileSet,
+	doc *ast.CommentGroup,
+	known []string,
+) attributes {
+	attrs := make(attributes)
+	if doc == nil {
+		return attrs
+	}
+
+	for _, comment := range doc.List {
+		content, offset, ok := directiveContent(comment.Text)
+		if !ok {
+			continue
+		}
+		parts := splitArgs(content, offset)
+		if len(parts) == 0 || !strings.HasPrefix(parts[0].Text, "enumr:@") {
+			continue
+		}
+		parts[0].Text = strings.TrimPrefix(parts[0].Text, "enumr:")
+		parts[0].Offset += len("enumr:")
+
+		pos := commentPosition(fset, comment)
+		for _, part := range parts {
+			argPos := argPosition(pos, part.Offset)
+			if !strings.HasPrefix(part.Text, "@") {
+				warnAt(
+					ctx,
+					logger,
+					RuleDirectiveSyntax,
+					argPos,
+					"skipping argument %q in attribute directive: attributes start with \"@\"",
+					part.Text,
+				)
+				continue
+			}
+			attrs.add(ctx, logger, argPos, part.Text, known)
+		}
+	}
+	return attrs
+}
+
+// add parses an "@key=value" argument into attrs, warning if the key is not
+// in known and suggesting the closest known key. The value of an attribute
+// given as just "@key" is empty. For consistency with field arguments,
+// "@key:value" is accepted as well.
+func (attrs attributes) add(
+	ctx context.Context,
+	logger *slog.Logger,
+	pos token.Position,
+	arg string,
+	known []string,
+) {
+	arg = strings.TrimPrefix(arg, "@")
+	key, value := arg, ""
+	if i := strings.IndexAny(arg, "=:"); i >= 0 {
+		key, value = arg[:i], arg[i+1:]
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	if !slices.Contains(known, key) {
+		suggestion := goenumr.Suggest(key, known)
+		if suggestion == "" {
+			warnAt(ctx, logger, RuleUnknownAttribute, pos, "unknown attribute %q", "@"+key)
+			return
+		}
+		warnFixAt(
+			ctx,
+			logger,
+			RuleUnknownAttribute,
+			pos,
+			Fix{Old: "@" + key, New: "@" + suggestion},
+			"unknown attribute %q; did you mean %q?",
+			"@"+key,
+			"@"+suggestion,
+		)
+		return
+	}
+	attrs[key] = attribute{Value: value, Pos: pos}
+}
+
+// list returns the comma-separated values of an attribute, ignoring empty
+// entries.
+func (a attribute) list() []string {
+	var values []string
+	for _, value := range strings.Split(a.Value, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// directiveContent returns the text of an enumr directive comment without the
+// comment marker, its byte offset in the comment, and whether the comment is a
+// directive at all.
+func directiveContent(text string) (string, int, bool) {
+	if !strings.HasPrefix(text, "//") {
+		return "", 0, false
+	}
+	// Normalize: "// enumr:Name" -> "enumr:Name"
+	rest := strings.TrimLeftFunc(strings.TrimPrefix(text, "//"), unicode.IsSpace)
+	content := strings.TrimRightFunc(rest, unicode.IsSpace)
+	return content, len(text) - len(rest), strings.HasPrefix(content, "enumr:")
+}
+
+// parseMode controls how Parse functions match their input.
+type parseMode struct {
+	// Fold matches case-insensitively using Unicode simple case folding.
+	Fold bool
+	// Trim ignores leading and trailing white space.
+	Trim bool
+	// Normalize is "NFC" or "NFKC" to apply Unicode normalization to the
+	// input before matching, or empty.
+	Normalize string
+}
+
+// newParseMode parses a comma-separated list of parse modes. "exact" and the
+// empty string select exact matching.
+func newParseMode(spec string) (parseMode, error) {
+	var mode parseMode
+	for _, name := range strings.Split(spec, ",") {
+		switch name := strings.TrimSpace(name); strings.ToLower(name) {
+		case "", "exact":
+		case "fold":
+			mode.Fold = true
+		case "trim":
+			mode.Trim = true
+		case "nfc", "nfkc":
+			if mode.Normalize != "" {
+				return parseMode{}, errorAt(
+					RuleInvalidAttribute,
+					token.Position{},
+					"parse modes nfc and nfkc cannot be combined",
+				)
+			}
+			mode.Normalize = strings.ToUpper(name)
+		default:
+			return parseMode{}, errorAt(
+				RuleInvalidAttribute,
+				token.Position{},
+				"unknown parse mode %q (want exact, fold, trim, nfc or nfkc)",
+				name,
+			)
+		}
+	}
+	return mode, nil
+}
+
+// resolveParseMode returns the parse mode of a type: its @parse attribute if
+// set, otherwise the default from the options.
+func resolveParseMode(attrs attributes, opts Options) (parseMode, error) {
+	attr, ok := attrs["parse"]
+	if !ok {
+		return newParseMode(opts.Parse)
+	}
+	mode, err := newParseMode(attr.Value)
+	if diag, isDiag := err.(*Diagnostic); isDiag {
+		diag.Pos = attr.Pos
+	}
+	return mode, err
+}
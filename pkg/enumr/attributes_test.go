@@ -0,0 +1,98 @@
+package enumr
+
+import (
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestNewParseMode(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected parseMode
+		wantErr  bool
+	}{
+		{spec: "", expected: parseMode{}},
+		{spec: "exact", expected: parseMode{}},
+		{spec: "fold,trim", expected: parseMode{Fold: true, Trim: true}},
+		{spec: "Fold, NFKC", expected: parseMode{Fold: true, Normalize: "NFKC"}},
+		{spec: "nfc", expected: parseMode{Normalize: "NFC"}},
+		{spec: "nfc,nfkc", wantErr: true},
+		{spec: "lower", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := newParseMode(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newParseMode(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("newParseMode(%q) = %+v; want %+v", tt.spec, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTypeAttributes(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("method.go", -1, 100)
+	file.SetLines([]int{0, 40})
+
+	doc := &ast.CommentGroup{List: []*ast.Comment{
+		{Slash: file.Pos(0), Text: `//enumr:@parse=nfd @color=red`},
+		{Slash: file.Pos(40), Text: `//enumr:CreditCard Code:CC`},
+	}}
+
+	handler := &recordingHandler{}
+	attrs := parseTypeAttributes(t.Context(), slog.New(handler), fset, doc)
+
+	want := attributes{
+		"parse": {Value: "nfd", Pos: token.Position{Filename: "method.go", Offset: 8, Line: 1, Column: 9}},
+	}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("parseTypeAttributes() = %v; want %v", attrs, want)
+	}
+	if len(handler.records) != 1 {
+		t.Errorf("got %d warnings, want 1 for the unknown attribute", len(handler.records))
+	}
+
+	_, err := resolveParseMode(attrs, Options{Parse: "fold"})
+	diags := Diagnostics(err)
+	if len(diags) != 1 || diags[0].Rule != RuleInvalidAttribute || diags[0].Pos != want["parse"].Pos {
+		t.Errorf("resolveParseMode() error = %v; want %s at %v", err, RuleInvalidAttribute, want["parse"].Pos)
+	}
+
+	mode, err := resolveParseMode(attributes{}, Options{Parse: "fold"})
+	if err != nil || !mode.Fold {
+		t.Errorf("resolveParseMode() without attribute = %+v, %v; want the default from Options", mode, err)
+	}
+}
+
+func TestSourceImports(t *testing.T) {
+	enums := []enumInfo{
+		{TypeName: "A", ParseMode: parseMode{Fold: true}},
+		{TypeName: "B", ParseMode: parseMode{Trim: true, Normalize: "NFC"}},
+	}
+	want := []importSpec{
+		{Path: "fmt"},
+		{Path: "golang.org/x/text/unicode/norm"},
+		{Path: "strings"},
+	}
+	if got := sourceImports(enums); !reflect.DeepEqual(got, want) {
+		t.Errorf("sourceImports() = %v; want %v", got, want)
+	}
+	if got := sourceImports(nil); !reflect.DeepEqual(got, []importSpec{{Path: "fmt"}}) {
+		t.Errorf("sourceImports(nil) = %v; want only fmt", got)
+	}
+
+	// The runtime package is only imported on request
+	enums[0].Runtime = true
+	want = slices.Insert(want, 1, runtimeImport)
+	if got := sourceImports(enums); !reflect.DeepEqual(got, want) {
+		t.Errorf("sourceImports() with Runtime = %v; want %v", got, want)
+	}
+}
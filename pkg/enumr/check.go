@@ -0,0 +1,89 @@
+package enumr
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"log/slog"
+	"sync"
+)
+
+// CheckDirectives parses the enumr directives in doc, the doc comment of the
+// type declared by spec, as the Generator does, and returns the problems it
+// would report about them: directive syntax, unknown fields and attributes,
+// field values that cannot be of the field's type and invalid parse modes.
+// info, if not nil, resolves the types of the struct fields and the
+// identifiers that field values refer to.
+func CheckDirectives(
+	fset *token.FileSet,
+	info *types.Info,
+	spec *ast.TypeSpec,
+	doc *ast.CommentGroup,
+) []Diagnostic {
+	handler := &collectingHandler{}
+	logger := slog.New(handler)
+	ctx := context.Background()
+
+	attrs := parseTypeAttributes(ctx, logger, fset, doc)
+	var scope *types.Scope
+	if info != nil {
+		if obj := info.Defs[spec.Name]; obj != nil {
+			scope = obj.Parent()
+		}
+	}
+	parseDirectives(ctx, logger, fset, doc, extractFields(info, spec), scope)
+
+	diags := handler.diags
+	if _, err := resolveParseMode(attrs, Options{}); err != nil {
+		diags = append(diags, Diagnostics(err)...)
+	}
+	return diags
+}
+
+// CheckInstanceDirectives parses the "//enumr:@key=value" attribute lines in
+// doc, the doc comment of a var declaring an instance in manual mode, as the
+// Generator does, and returns the problems it would report about them:
+// directive syntax and unknown attributes.
+func CheckInstanceDirectives(fset *token.FileSet, doc *ast.CommentGroup) []Diagnostic {
+	handler := &collectingHandler{}
+	parseAttributeLines(context.Background(), slog.New(handler), fset, doc, instanceAttributes)
+	return handler.diags
+}
+
+// collectingHandler is a slog.Handler that collects warnings logged with
+// warnAt or warnFixAt as diagnostics.
+type collectingHandler struct {
+	mu    sync.Mutex
+	diags []Diagnostic
+}
+
+func (h *collectingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn
+}
+
+func (h *collectingHandler) Handle(_ context.Context, r slog.Record) error {
+	diag := Diagnostic{Severity: SeverityWarning, Message: r.Message}
+	r.Attrs(func(a slog.Attr) bool {
+		switch v := a.Value.Any().(type) {
+		case string:
+			if a.Key == "rule" {
+				diag.Rule = v
+			}
+		case token.Position:
+			diag.Pos = v
+		case Fix:
+			diag.Fix = &v
+		}
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.diags = append(h.diags, diag)
+	return nil
+}
+
+func (h *collectingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *collectingHandler) WithGroup(string) slog.Handler { return h }
@@ -0,0 +1,92 @@
+package enumr
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestCheckDirectives(t *testing.T) {
+	const src = `package payment
+
+// enumr:@parse=fodl
+// enumr:Card Code:CC Descr:"Credit card" @alis=card
+// enumr:Cash Code:CA Fee:1.5 Extra:x
+// enumr:Cheque Code:CH ode:CH
+type Method struct {
+	Code string
+	Desc string
+	Fee  int
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "method.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decl := file.Decls[0].(*ast.GenDecl)
+	spec := decl.Specs[0].(*ast.TypeSpec)
+
+	type result struct {
+		rule     string
+		severity Severity
+		pos      string
+		fix      *Fix
+	}
+	var got []result
+	for _, d := range CheckDirectives(fset, nil, spec, decl.Doc) {
+		got = append(got, result{rule: d.Rule, severity: d.Severity, pos: d.Pos.String(), fix: d.Fix})
+	}
+
+	want := []result{
+		{
+			rule:     RuleUnknownAttribute,
+			severity: SeverityWarning,
+			pos:      "method.go:4:43",
+			fix:      &Fix{Old: "@alis", New: "@alias"},
+		},
+		{
+			rule:     RuleUnknownField,
+			severity: SeverityWarning,
+			pos:      "method.go:4:23",
+			fix:      &Fix{Old: "Descr", New: "Desc"},
+		},
+		{rule: RuleInvalidValue, severity: SeverityWarning, pos: "method.go:5:23"},
+		{rule: RuleUnknownField, severity: SeverityWarning, pos: "method.go:5:31"},
+		{
+			rule:     RuleUnknownField,
+			severity: SeverityWarning,
+			pos:      "method.go:6:25",
+			fix:      &Fix{Old: "ode", New: "Code"},
+		},
+		{rule: RuleInvalidAttribute, severity: SeverityError, pos: "method.go:3:10"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckDirectives() = %v; want %v", got, want)
+	}
+}
+
+func TestCheckInstanceDirectives(t *testing.T) {
+	const src = `package payment
+
+// enumr:@alias=card @deprecatd="use Cash" order:1
+var Card = Method{Code: "CC"}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "method.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decl := file.Decls[0].(*ast.GenDecl)
+
+	var got []string
+	for _, d := range CheckInstanceDirectives(fset, decl.Doc) {
+		got = append(got, d.Rule+" "+d.Pos.String())
+	}
+	want := []string{RuleUnknownAttribute + " method.go:3:22", RuleDirectiveSyntax + " method.go:3:44"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckInstanceDirectives() = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package enumr
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// GeneratedFile is a file generated by enumr, together with the types it was
+// generated for that no longer need it.
+type GeneratedFile struct {
+	Path string
+	// Types are the types the file provides Parse functions for.
+	Types []string
+	// Missing are the Types that are no longer declared in the package, or
+	// that no longer have directives or var instances.
+	Missing []string
+}
+
+// Orphaned reports whether none of the file's types need it any more, so
+// that it can be deleted.
+func (f GeneratedFile) Orphaned() bool {
+	return len(f.Missing) == len(f.Types)
+}
+
+// GeneratedFiles returns the files in dir that were generated by enumr and
+// have at least one missing type, using only a syntactic scan so that it
+// works on packages that no longer build.
+func GeneratedFiles(dir string) ([]GeneratedFile, error) {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := sourceFiles(files)
+	var stale []GeneratedFile
+	for _, file := range files {
+		if !IsGeneratedFile(file) {
+			continue
+		}
+		generated := GeneratedFile{
+			Path:  fset.Position(file.Package).Filename,
+			Types: generatedTypes(file),
+		}
+		for _, typeName := range generated.Types {
+			if !hasInstances(sources, typeName) {
+				generated.Missing = append(generated.Missing, typeName)
+			}
+		}
+		if len(generated.Missing) > 0 {
+			stale = append(stale, generated)
+		}
+	}
+	return stale, nil
+}
+
+// hasInstances reports whether the type is declared in the files and has
+// either directives or var instances.
+func hasInstances(files []*ast.File, typeName string) bool {
+	if len(declaredTypes(files, []string{typeName})) == 0 {
+		return false
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch genDecl.Tok {
+			case token.TYPE:
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					if typeSpec.Name.Name != typeName {
+						continue
+					}
+					if hasDirectives(genDecl.Doc) || hasDirectives(typeSpec.Doc) {
+						return true
+					}
+				}
+			case token.VAR:
+				if declaresInputs(genDecl, []string{typeName}) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasDirectives reports whether the comment group contains an instance
+// directive.
+func hasDirectives(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	return slices.ContainsFunc(doc.List, func(comment *ast.Comment) bool {
+		content, _, ok := directiveContent(comment.Text)
+		name := strings.TrimSpace(strings.TrimPrefix(content, "enumr:"))
+		return ok && name != "" && !strings.HasPrefix(name, "@")
+	})
+}
@@ -0,0 +1,47 @@
+package enumr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"payment.go": "package payment\n\n//enumr:CreditCard\ntype Method struct{}\n\n" +
+			"type Status struct{}\n\nvar Active = Status{}\n\ntype Empty struct{}\n",
+		"method_enum.go": generatedHeader + "\n\npackage payment\n\nfunc ParseMethod(s string) {}\n",
+		"status_enum.go": generatedHeader + "\n\npackage payment\n\nfunc ParseStatus(s string) {}\n",
+		"empty_enum.go":  generatedHeader + "\n\npackage payment\n\nfunc ParseEmpty(s string) {}\n",
+		"gone_enum.go":   generatedHeader + "\n\npackage payment\n\nfunc ParseGone(s string) {}\n",
+		"mixed_enum.go": generatedHeader + "\n\npackage payment\n\n" +
+			"func ParseMethod(s string) {}\nfunc ParseGone(s string) {}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := GeneratedFiles(dir)
+	if err != nil {
+		t.Fatalf("GeneratedFiles failed: %v", err)
+	}
+
+	want := []GeneratedFile{
+		{Path: filepath.Join(dir, "empty_enum.go"), Types: []string{"Empty"}, Missing: []string{"Empty"}},
+		{Path: filepath.Join(dir, "gone_enum.go"), Types: []string{"Gone"}, Missing: []string{"Gone"}},
+		{Path: filepath.Join(dir, "mixed_enum.go"), Types: []string{"Method", "Gone"}, Missing: []string{"Gone"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GeneratedFiles() = %v; want %v", got, want)
+	}
+
+	for i, orphaned := range []bool{true, true, false} {
+		if got[i].Orphaned() != orphaned {
+			t.Errorf("%s: Orphaned() = %v; want %v", got[i].Path, got[i].Orphaned(), orphaned)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package enumr
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind describes how an enum changed between two snapshots.
+type ChangeKind string
+
+const (
+	// ChangeTypeRemoved means the enum type no longer exists.
+	ChangeTypeRemoved ChangeKind = "type-removed"
+	// ChangeRemoved means an instance and its wire value no longer exist.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeWire means an instance marshals to a different string.
+	ChangeWire ChangeKind = "wire-changed"
+	// ChangeRenamed means an instance was renamed but kept its wire value.
+	ChangeRenamed ChangeKind = "renamed"
+	// ChangeAliasRemoved means an alias no longer parses to the instance.
+	ChangeAliasRemoved ChangeKind = "alias-removed"
+	// ChangeID means the explicit ID of an instance changed or was removed.
+	ChangeID ChangeKind = "id-changed"
+	// ChangeFields means the field values of an instance changed.
+	ChangeFields ChangeKind = "fields-changed"
+	// ChangeAdded means a new instance was added.
+	ChangeAdded ChangeKind = "added"
+)
+
+// Change is a single difference between a baseline and the current enum model.
+type Change struct {
+	Kind     ChangeKind
+	Type     string
+	Instance string
+	Old      string
+	New      string
+	// Breaking reports whether values accepted by the baseline are no longer
+	// parsed, or parse to something else.
+	Breaking bool
+}
+
+// String returns a human-readable description of the change.
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeTypeRemoved:
+		return fmt.Sprintf("%s: type removed", c.Type)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s.%s: removed (wire value %q no longer parses)", c.Type, c.Instance, c.Old)
+	case ChangeWire:
+		return fmt.Sprintf("%s.%s: wire value changed from %q to %q", c.Type, c.Instance, c.Old, c.New)
+	case ChangeRenamed:
+		return fmt.Sprintf("%s.%s: renamed to %s", c.Type, c.Old, c.New)
+	case ChangeAliasRemoved:
+		return fmt.Sprintf("%s.%s: alias %q removed (no longer parses)", c.Type, c.Instance, c.Old)
+	case ChangeID:
+		return fmt.Sprintf("%s.%s: id changed from %s to %s", c.Type, c.Instance, c.Old, c.New)
+	case ChangeFields:
+		return fmt.Sprintf("%s.%s: fields changed from %s to %s", c.Type, c.Instance, c.Old, c.New)
+	case ChangeAdded:
+		return fmt.Sprintf("%s.%s: added (wire value %q)", c.Type, c.Instance, c.New)
+	default:
+		return fmt.Sprintf("%s.%s: %s", c.Type, c.Instance, c.Kind)
+	}
+}
+
+// Compare reports the differences between a baseline snapshot and the current one.
+// Types present only in the current snapshot are ignored.
+func Compare(base, current *Snapshot) []Change {
+	var changes []Change
+	for _, baseType := range base.Types {
+		currentType, ok := current.Type(baseType.Name)
+		if !ok {
+			changes = append(changes, Change{
+				Kind:     ChangeTypeRemoved,
+				Type:     baseType.Name,
+				Breaking: true,
+			})
+			continue
+		}
+		changes = append(changes, compareType(baseType, currentType)...)
+	}
+	return changes
+}
+
+// compareType reports the differences between two versions of the same type.
+// Instances are matched by name first and then by wire value, so that a
+// renamed instance which keeps its wire value is not reported as removed.
+func compareType(base, current TypeSnapshot) []Change {
+	var changes []Change
+
+	currentByName := make(map[string]InstanceSnapshot, len(current.Instances))
+	currentByWire := make(map[string]InstanceSnapshot, len(current.Instances))
+	for _, instance := range current.Instances {
+		currentByName[instance.Name] = instance
+		currentByWire[instance.Wire] = instance
+	}
+
+	matched := make(map[string]bool, len(current.Instances))
+	for _, old := range base.Instances {
+		if cur, ok := currentByName[old.Name]; ok {
+			matched[cur.Name] = true
+			if cur.Wire != old.Wire {
+				changes = append(changes, Change{
+					Kind:     ChangeWire,
+					Type:     base.Name,
+					Instance: old.Name,
+					Old:      old.Wire,
+					New:      cur.Wire,
+					Breaking: true,
+				})
+			}
+			changes = appendAliasChanges(changes, base.Name, old, cur)
+			changes = appendIDChange(changes, base.Name, old, cur)
+			changes = appendFieldChange(changes, base.Name, old, cur)
+			continue
+		}
+
+		if cur, ok := currentByWire[old.Wire]; ok && !matched[cur.Name] {
+			matched[cur.Name] = true
+			changes = append(changes, Change{
+				Kind:     ChangeRenamed,
+				Type:     base.Name,
+				Instance: cur.Name,
+				Old:      old.Name,
+				New:      cur.Name,
+			})
+			changes = appendAliasChanges(changes, base.Name, old, cur)
+			changes = appendIDChange(changes, base.Name, old, cur)
+			changes = appendFieldChange(changes, base.Name, old, cur)
+			continue
+		}
+
+		changes = append(changes, Change{
+			Kind:     ChangeRemoved,
+			Type:     base.Name,
+			Instance: old.Name,
+			Old:      old.Wire,
+			Breaking: true,
+		})
+	}
+
+	for _, instance := range current.Instances {
+		if matched[instance.Name] {
+			continue
+		}
+		changes = append(changes, Change{
+			Kind:     ChangeAdded,
+			Type:     base.Name,
+			Instance: instance.Name,
+			New:      instance.Wire,
+		})
+	}
+
+	return changes
+}
+
+// appendAliasChanges records a breaking change for each alias of old that no
+// longer parses to cur, either as an alias or as its wire value.
+func appendAliasChanges(changes []Change, typeName string, old, cur InstanceSnapshot) []Change {
+	for _, alias := range old.Aliases {
+		if alias == cur.Wire || slices.Contains(cur.Aliases, alias) {
+			continue
+		}
+		changes = append(changes, Change{
+			Kind:     ChangeAliasRemoved,
+			Type:     typeName,
+			Instance: cur.Name,
+			Old:      alias,
+			Breaking: true,
+		})
+	}
+	return changes
+}
+
+// appendIDChange records a breaking change if old has an ID and cur does not
+// have the same one, since values encoded with MarshalBinary store the ID.
+func appendIDChange(changes []Change, typeName string, old, cur InstanceSnapshot) []Change {
+	if old.ID == nil || (cur.ID != nil && *cur.ID == *old.ID) {
+		return changes
+	}
+	newID := "none"
+	if cur.ID != nil {
+		newID = strconv.Itoa(*cur.ID)
+	}
+	return append(changes, Change{
+		Kind:     ChangeID,
+		Type:     typeName,
+		Instance: cur.Name,
+		Old:      strconv.Itoa(*old.ID),
+		New:      newID,
+		Breaking: true,
+	})
+}
+
+// appendFieldChange records a field change if the values of old and cur differ.
+func appendFieldChange(changes []Change, typeName string, old, cur InstanceSnapshot) []Change {
+	if maps.Equal(cur.Fields, old.Fields) {
+		return changes
+	}
+	return append(changes, Change{
+		Kind:     ChangeFields,
+		Type:     typeName,
+		Instance: cur.Name,
+		Old:      formatFields(old.Fields),
+		New:      formatFields(cur.Fields),
+	})
+}
+
+// formatFields renders field values in a stable order.
+func formatFields(fields map[string]string) string {
+	parts := make([]string, 0, len(fields))
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		parts = append(parts, name+":"+fields[name])
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
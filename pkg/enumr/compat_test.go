@@ -0,0 +1,164 @@
+package enumr
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	base := &Snapshot{Types: []TypeSnapshot{
+		{
+			Name: "Method",
+			Instances: []InstanceSnapshot{
+				{Name: "CreditCard", Wire: "CC", Fields: map[string]string{"Code": `"CC"`}},
+				{Name: "PayPal", Wire: "PP", Fields: map[string]string{"Code": `"PP"`}},
+				{Name: "Cheque", Wire: "CH", Fields: map[string]string{"Code": `"CH"`}},
+				{Name: "Cash", Wire: "CA", Fields: map[string]string{"Code": `"CA"`, "Desc": `"Cash"`}},
+			},
+		},
+		{Name: "Removed", Instances: []InstanceSnapshot{{Name: "Gone", Wire: "gone"}}},
+	}}
+	current := &Snapshot{Types: []TypeSnapshot{
+		{
+			Name: "Method",
+			Instances: []InstanceSnapshot{
+				{Name: "CreditCard", Wire: "CARD", Fields: map[string]string{"Code": `"CARD"`}},
+				{Name: "Paypal", Wire: "PP", Fields: map[string]string{"Code": `"PP"`}},
+				{Name: "Cash", Wire: "CA", Fields: map[string]string{"Code": `"CA"`, "Desc": `"Coins"`}},
+				{Name: "Bank", Wire: "BT", Fields: map[string]string{"Code": `"BT"`}},
+			},
+		},
+	}}
+
+	want := []Change{
+		{Kind: ChangeWire, Type: "Method", Instance: "CreditCard", Old: "CC", New: "CARD", Breaking: true},
+		{
+			Kind:     ChangeFields,
+			Type:     "Method",
+			Instance: "CreditCard",
+			Old:      `{Code:"CC"}`,
+			New:      `{Code:"CARD"}`,
+		},
+		{Kind: ChangeRenamed, Type: "Method", Instance: "Paypal", Old: "PayPal", New: "Paypal"},
+		{Kind: ChangeRemoved, Type: "Method", Instance: "Cheque", Old: "CH", Breaking: true},
+		{
+			Kind:     ChangeFields,
+			Type:     "Method",
+			Instance: "Cash",
+			Old:      `{Code:"CA" Desc:"Cash"}`,
+			New:      `{Code:"CA" Desc:"Coins"}`,
+		},
+		{Kind: ChangeAdded, Type: "Method", Instance: "Bank", New: "BT"},
+		{Kind: ChangeTypeRemoved, Type: "Removed", Breaking: true},
+	}
+
+	got := Compare(base, current)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCompareAliases(t *testing.T) {
+	base := &Snapshot{Types: []TypeSnapshot{{
+		Name: "Method",
+		Instances: []InstanceSnapshot{
+			{Name: "CreditCard", Wire: "CC", Aliases: []string{"card", "cc_old"}},
+			{Name: "PayPal", Wire: "PP", Aliases: []string{"paypal"}},
+		},
+	}}}
+	current := &Snapshot{Types: []TypeSnapshot{{
+		Name: "Method",
+		Instances: []InstanceSnapshot{
+			{Name: "CreditCard", Wire: "card"},
+			{Name: "PayPal", Wire: "PP", Aliases: []string{"paypal", "pay_pal"}},
+		},
+	}}}
+
+	want := []Change{
+		{Kind: ChangeWire, Type: "Method", Instance: "CreditCard", Old: "CC", New: "card", Breaking: true},
+		{Kind: ChangeAliasRemoved, Type: "Method", Instance: "CreditCard", Old: "cc_old", Breaking: true},
+	}
+
+	got := Compare(base, current)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCompareIDs(t *testing.T) {
+	id := func(n int) *int { return &n }
+	base := &Snapshot{Types: []TypeSnapshot{{
+		Name: "Method",
+		Instances: []InstanceSnapshot{
+			{Name: "CreditCard", Wire: "CC", ID: id(7)},
+			{Name: "PayPal", Wire: "PP", ID: id(8)},
+			{Name: "Cheque", Wire: "CH", ID: id(9)},
+		},
+	}}}
+	current := &Snapshot{Types: []TypeSnapshot{{
+		Name: "Method",
+		Instances: []InstanceSnapshot{
+			{Name: "CreditCard", Wire: "CC", ID: id(8)},
+			{Name: "Paypal", Wire: "PP", ID: id(8)},
+			{Name: "Cheque", Wire: "CH"},
+		},
+	}}}
+
+	want := []Change{
+		{Kind: ChangeID, Type: "Method", Instance: "CreditCard", Old: "7", New: "8", Breaking: true},
+		{Kind: ChangeRenamed, Type: "Method", Instance: "Paypal", Old: "PayPal", New: "Paypal"},
+		{Kind: ChangeID, Type: "Method", Instance: "Cheque", Old: "9", New: "none", Breaking: true},
+	}
+
+	got := Compare(base, current)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	snapshot := newSnapshot([]enumInfo{
+		{
+			TypeName:   "MyEnum",
+			CaseFormat: "snake_case",
+			Instances:  []instanceData{{Name: "ValueOne"}, {Name: "ValueTwo"}},
+		},
+	})
+
+	if changes := Compare(snapshot, snapshot); len(changes) != 0 {
+		t.Errorf("Compare() of identical snapshots = %v; want no changes", changes)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	snapshot := newSnapshot([]enumInfo{
+		{
+			TypeName:     "PaymentMethod",
+			MarshalField: "Code",
+			Instances: []instanceData{
+				{Name: "CreditCard", Fields: map[string]string{"Code": `"CC"`}, Aliases: []string{"card"}},
+				{Name: "Custom", Fields: map[string]string{"Code": "customCode"}},
+			},
+		},
+	})
+
+	if got := snapshot.Types[0].Instances[0].Wire; got != "CC" {
+		t.Errorf("wire value of string literal = %q; want %q", got, "CC")
+	}
+	if got := snapshot.Types[0].Instances[1].Wire; got != "customCode" {
+		t.Errorf("wire value of constant reference = %q; want %q", got, "customCode")
+	}
+
+	var buf bytes.Buffer
+	if _, err := snapshot.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	decoded, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, snapshot) {
+		t.Errorf("ReadSnapshot() = %+v; want %+v", decoded, snapshot)
+	}
+}
@@ -0,0 +1,27 @@
+package enumr
+
+// resolveDefault sets Default to the instance with a @default attribute,
+// which the zero value of the type stands for. At most one instance of a type
+// may be the default.
+func (e *enumInfo) resolveDefault() error {
+	for _, instance := range e.Instances {
+		attr, ok := instance.Attributes["default"]
+		if !ok {
+			continue
+		}
+		if attr.Value != "" {
+			return errorAt(RuleInvalidAttribute, attr.Pos, "@default of %s does not take a value", instance.Name)
+		}
+		if e.Default != "" {
+			return errorAt(
+				RuleInvalidAttribute,
+				attr.Pos,
+				"%s and %s are both marked @default",
+				e.Default,
+				instance.Name,
+			)
+		}
+		e.Default = instance.Name
+	}
+	return nil
+}
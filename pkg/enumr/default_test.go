@@ -0,0 +1,61 @@
+package enumr
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestResolveDefault(t *testing.T) {
+	pos := token.Position{Filename: "method.go", Line: 4, Column: 20}
+	tests := []struct {
+		name      string
+		instances []instanceData
+		expected  string
+		wantErr   bool
+	}{
+		{
+			name:      "None",
+			instances: []instanceData{{Name: "Card"}},
+		},
+		{
+			name: "One",
+			instances: []instanceData{
+				{Name: "Unknown", Attributes: attributes{"default": {Pos: pos}}},
+				{Name: "Card"},
+			},
+			expected: "Unknown",
+		},
+		{
+			name: "Two",
+			instances: []instanceData{
+				{Name: "Unknown", Attributes: attributes{"default": {Pos: pos}}},
+				{Name: "Card", Attributes: attributes{"default": {Pos: pos}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Value",
+			instances: []instanceData{
+				{Name: "Unknown", Attributes: attributes{"default": {Value: "yes", Pos: pos}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enum := enumInfo{TypeName: "Method", Instances: tt.instances}
+			err := enum.resolveDefault()
+			if tt.wantErr {
+				diags := Diagnostics(err)
+				if len(diags) != 1 || diags[0].Rule != RuleInvalidAttribute || diags[0].Pos != pos {
+					t.Errorf("resolveDefault() error = %v; want %s at %v", err, RuleInvalidAttribute, pos)
+				}
+				return
+			}
+			if err != nil || enum.Default != tt.expected {
+				t.Errorf("resolveDefault() = %q, %v; want %q", enum.Default, err, tt.expected)
+			}
+		})
+	}
+}
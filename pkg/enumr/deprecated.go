@@ -0,0 +1,54 @@
+package enumr
+
+import (
+	"cmp"
+	"go/ast"
+	"slices"
+	"strings"
+)
+
+// defaultDeprecation is the notice of instances deprecated without a message.
+const defaultDeprecation = "this value is no longer supported."
+
+// resolveDeprecations sets the Deprecated notice of each instance from its
+// @deprecated attribute or, for manual instances, from a "Deprecated:"
+// paragraph in the doc comment of its var.
+func (e *enumInfo) resolveDeprecations() {
+	for i := range e.Instances {
+		instance := &e.Instances[i]
+		if attr, ok := instance.Attributes["deprecated"]; ok {
+			instance.Deprecated = cmp.Or(attr.Value, defaultDeprecation)
+		} else {
+			instance.Deprecated = deprecationNotice(instance.Doc)
+		}
+	}
+}
+
+// HasDeprecated reports whether any instance of the enum is deprecated.
+func (e enumInfo) HasDeprecated() bool {
+	return slices.ContainsFunc(e.Instances, func(instance instanceData) bool {
+		return instance.Deprecated != ""
+	})
+}
+
+// deprecationNotice returns the text of the "Deprecated:" paragraph of a doc
+// comment, or the empty string if there is none.
+func deprecationNotice(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+
+	var notice []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if len(notice) > 0 {
+			if line == "" {
+				break
+			}
+			notice = append(notice, line)
+		} else if rest, ok := strings.CutPrefix(line, "Deprecated:"); ok {
+			notice = append(notice, cmp.Or(strings.TrimSpace(rest), defaultDeprecation))
+		}
+	}
+	return strings.Join(notice, " ")
+}
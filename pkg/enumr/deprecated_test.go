@@ -0,0 +1,28 @@
+package enumr
+
+import (
+	"go/ast"
+	"strings"
+	"testing"
+)
+
+func TestDeprecationNotice(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "// Cheque pays by cheque.", expected: ""},
+		{text: "// Cheque pays by cheque.\n//\n// Deprecated: use\n// BankTransfer.", expected: "use BankTransfer."},
+		{text: "// Deprecated:", expected: defaultDeprecation},
+	}
+
+	for _, tt := range tests {
+		var doc ast.CommentGroup
+		for _, line := range strings.Split(tt.text, "\n") {
+			doc.List = append(doc.List, &ast.Comment{Text: line})
+		}
+		if got := deprecationNotice(&doc); got != tt.expected {
+			t.Errorf("deprecationNotice(%q) = %q; want %q", tt.text, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package enumr
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"log/slog"
+)
+
+// Severity is the severity of a Diagnostic.
+type Severity string
+
+const (
+	// SeverityError marks a problem that stops generation.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a problem that is reported but does not stop generation.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule IDs identify the kind of problem a Diagnostic reports.
+const (
+	RuleLoad                = "enumr/load"
+	RuleTypeNotFound        = "enumr/type-not-found"
+	RuleNoInstances         = "enumr/no-instances"
+	RuleMissingMarshalField = "enumr/missing-marshal-field"
+	RuleDirectiveSyntax     = "enumr/directive-syntax"
+	RuleUnknownField        = "enumr/unknown-field"
+	RuleUnknownAttribute    = "enumr/unknown-attribute"
+	RuleInvalidValue        = "enumr/invalid-value"
+	RuleInvalidAttribute    = "enumr/invalid-attribute"
+	RuleDuplicateValue      = "enumr/duplicate-value"
+	RuleDuplicateAlias      = "enumr/duplicate-alias"
+	RuleDuplicateID         = "enumr/duplicate-id"
+	RuleSetSeparator        = "enumr/set-separator"
+	RuleLock                = "enumr/lock"
+	RuleGenerate            = "enumr/generate"
+	RuleTypeCheck           = "enumr/typecheck"
+)
+
+// Diagnostic is a problem found while resolving or generating enums. Errors
+// returned by the Generator are, or wrap, *Diagnostic values. Warnings are
+// logged with the diagnostic's rule, position and fix as the "rule", "pos"
+// and "fix" attributes.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Pos      token.Position
+	Message  string
+	// Fix is a suggested correction of the problem, if any.
+	Fix *Fix
+}
+
+// Fix is a suggested correction of a Diagnostic: replacing the text Old at the
+// diagnostic's position with New.
+type Fix struct {
+	Old string
+	New string
+}
+
+// Error implements the error interface, formatting the diagnostic like a
+// compiler message.
+func (d *Diagnostic) Error() string {
+	if d.Pos.Filename == "" && !d.Pos.IsValid() {
+		return d.Message
+	}
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
+
+// Diagnostics returns the diagnostics carried by err, or nil if err does not
+// wrap any *Diagnostic.
+func Diagnostics(err error) []Diagnostic {
+	var diags []Diagnostic
+	collectDiagnostics(err, &diags)
+	return diags
+}
+
+func collectDiagnostics(err error, diags *[]Diagnostic) {
+	switch e := err.(type) {
+	case *Diagnostic:
+		*diags = append(*diags, *e)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			collectDiagnostics(inner, diags)
+		}
+	case interface{ Unwrap() error }:
+		collectDiagnostics(e.Unwrap(), diags)
+	}
+}
+
+// errorAt returns an error diagnostic at the given position.
+func errorAt(rule string, pos token.Position, format string, args ...any) *Diagnostic {
+	return &Diagnostic{
+		Rule:     rule,
+		Severity: SeverityError,
+		Pos:      pos,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// warnAt logs a warning diagnostic at the given position.
+func warnAt(
+	ctx context.Context,
+	logger *slog.Logger,
+	rule string,
+	pos token.Position,
+	format string,
+	args ...any,
+) {
+	logger.LogAttrs(
+		ctx,
+		slog.LevelWarn,
+		fmt.Sprintf(format, args...),
+		slog.String("rule", rule),
+		slog.Any("pos", pos),
+	)
+}
+
+// warnFixAt logs a warning diagnostic like warnAt, with a suggested fix as the
+// "fix" attribute.
+func warnFixAt(
+	ctx context.Context,
+	logger *slog.Logger,
+	rule string,
+	pos token.Position,
+	fix Fix,
+	format string,
+	args ...any,
+) {
+	logger.LogAttrs(
+		ctx,
+		slog.LevelWarn,
+		fmt.Sprintf(format, args...),
+		slog.String("rule", rule),
+		slog.Any("pos", pos),
+		slog.Any("fix", fix),
+	)
+}
@@ -0,0 +1,126 @@
+package enumr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// recordingHandler is a slog.Handler that keeps the records it handles.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func TestDiagnosticError(t *testing.T) {
+	tests := []struct {
+		d    *Diagnostic
+		want string
+	}{
+		{
+			d:    errorAt(RuleTypeNotFound, token.Position{}, "type %s not found", "Foo"),
+			want: "type Foo not found",
+		},
+		{
+			d:    errorAt(RuleLock, token.Position{Filename: "foo.enumr.lock"}, "out of date"),
+			want: "foo.enumr.lock: out of date",
+		},
+		{
+			d:    errorAt(RuleNoInstances, token.Position{Filename: "foo.go", Line: 3, Column: 6}, "no instances"),
+			want: "foo.go:3:6: no instances",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.d.Error(); got != tt.want {
+			t.Errorf("Error() = %q; want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	first := errorAt(RuleLock, token.Position{Filename: "a.lock"}, "first")
+	second := errorAt(RuleLock, token.Position{Filename: "a.lock"}, "second")
+	err := fmt.Errorf("wrapped: %w", errors.Join(first, second))
+
+	want := []Diagnostic{*first, *second}
+	if got := Diagnostics(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics() = %v; want %v", got, want)
+	}
+
+	if got := Diagnostics(errors.New("plain")); got != nil {
+		t.Errorf("Diagnostics() of plain error = %v; want nil", got)
+	}
+}
+
+func TestParseDirectivesWarnings(t *testing.T) {
+	fields := []fieldInfo{
+		{Name: "Code", Type: "string"},
+		{Name: "Desc", Type: "string"},
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("method.go", -1, 100)
+	file.SetLines([]int{0, 50})
+
+	doc := &ast.CommentGroup{List: []*ast.Comment{
+		{Slash: file.Pos(0), Text: `//enumr:CreditCard Code:CC Descr:"Credit Card"`},
+		{Slash: file.Pos(50), Text: `//enumr:PayPal Code:PP junk Desc:"Pay`},
+	}}
+
+	handler := &recordingHandler{}
+	instances := parseDirectives(t.Context(), slog.New(handler), fset, doc, fields, nil)
+	if len(instances) != 2 {
+		t.Fatalf("got %d instances, want 2", len(instances))
+	}
+	if want := (token.Position{Filename: "method.go", Offset: 50, Line: 2, Column: 1}); instances[1].Pos != want {
+		t.Errorf("instance Pos = %v; want %v", instances[1].Pos, want)
+	}
+
+	type warning struct {
+		rule string
+		pos  string
+	}
+	var got []warning
+	for _, r := range handler.records {
+		var w warning
+		r.Attrs(func(a slog.Attr) bool {
+			switch a.Key {
+			case "rule":
+				w.rule = a.Value.String()
+			case "pos":
+				w.pos = a.Value.Any().(token.Position).String()
+			}
+			return true
+		})
+		got = append(got, w)
+	}
+
+	want := []warning{
+		{rule: RuleUnknownField, pos: "method.go:1:28"},
+		{rule: RuleDirectiveSyntax, pos: "method.go:2:1"},
+		{rule: RuleDirectiveSyntax, pos: "method.go:2:24"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %v; want %v", got, want)
+	}
+}
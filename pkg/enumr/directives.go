@@ -4,18 +4,30 @@ import (
 	"context"
 	"fmt"
 	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"log/slog"
+	"math"
 	"strconv"
 	"strings"
 	"unicode"
+
+	goenumr "github.com/jmfrees/go-enumr"
 )
 
-// parseDirectives parses the comment group for enumr directives.
+// parseDirectives parses the comment group for enumr directives. scope, if
+// not nil, is the package scope that identifiers in field values must resolve
+// in.
 func parseDirectives(
 	ctx context.Context,
 	logger *slog.Logger,
+	fset *token.FileSet,
 	doc *ast.CommentGroup,
 	fields []fieldInfo,
+	scope *types.Scope,
 ) []instanceData {
 	if doc == nil {
 		return nil
@@ -23,7 +35,8 @@ func parseDirectives(
 
 	var instances []instanceData
 	for _, comment := range doc.List {
-		if instance, ok := parseDirective(ctx, logger, comment.Text, fields); ok {
+		pos := commentPosition(fset, comment)
+		if instance, ok := parseDirective(ctx, logger, pos, comment.Text, fields, scope); ok {
 			instances = append(instances, instance)
 		}
 	}
@@ -34,30 +47,46 @@ func parseDirectives(
 func parseDirective(
 	ctx context.Context,
 	logger *slog.Logger,
+	pos token.Position,
 	text string,
 	fields []fieldInfo,
+	scope *types.Scope,
 ) (instanceData, bool) {
-	if !strings.HasPrefix(text, "//") {
+	// Optimization: If it doesn't start with "enumr:", it's likely not for us.
+	// This avoids parsing unrelated comments like "//go:generate ..." and logging warnings.
+	content, offset, ok := directiveContent(text)
+	if !ok {
 		return instanceData{}, false
 	}
 
-	// Normalize: "// enumr:Name" -> "enumr:Name"
-	content := strings.TrimSpace(strings.TrimPrefix(text, "//"))
-
-	// Optimization: If it doesn't start with "enumr:", it's likely not for us.
-	// This avoids parsing unrelated comments like "//go:generate ..." and logging warnings.
-	if !strings.HasPrefix(content, "enumr:") {
+	// Attribute lines configure the type rather than declare an instance
+	if strings.HasPrefix(content, "enumr:@") {
 		return instanceData{}, false
 	}
 
+	if strings.Count(content, `"`)%2 != 0 {
+		warnAt(ctx, logger, RuleDirectiveSyntax, pos, "directive has an unterminated quote")
+	}
+
 	// Split the entire line into arguments
-	parts := splitArgs(content)
+	parts := splitArgs(content, offset)
 	if len(parts) == 0 {
 		return instanceData{}, false
 	}
 
+	// Attributes configure the instance rather than set fields
+	attrs := make(attributes)
+	args := parts[:1]
+	for _, part := range parts[1:] {
+		if strings.HasPrefix(part.Text, "@") {
+			attrs.add(ctx, logger, argPosition(pos, part.Offset), part.Text, instanceAttributes)
+			continue
+		}
+		args = append(args, part)
+	}
+
 	// Parse all arguments into a map
-	values := parseArgs(ctx, logger, parts)
+	values := parseArgs(ctx, logger, pos, args)
 
 	// Check for the 'enumr' key which defines the instance name
 	name, ok := values["enumr"]
@@ -69,7 +98,9 @@ func parseDirective(
 	delete(values, "enumr")
 
 	fieldMap := make(map[string]string)
+	fieldTypes := make(map[string]string, len(fields))
 	for _, field := range fields {
+		fieldTypes[field.Name] = field.Type
 		val, ok := values[field.Name]
 		if !ok {
 			continue
@@ -80,23 +111,212 @@ func parseDirective(
 		fieldMap[field.Name] = val
 	}
 
+	// Report keys that do not name a field, and values that cannot be of the
+	// field's type, in the order they were written
+	for _, part := range args[1:] {
+		key, raw, found := strings.Cut(part.Text, ":")
+		if !found {
+			continue
+		}
+		argPos := argPosition(pos, part.Offset)
+		typ, isField := fieldTypes[key]
+		if !isField {
+			warnUnknownField(ctx, logger, argPos, name, key, fields)
+			continue
+		}
+		if typ == "string" {
+			continue
+		}
+		if problem := checkFieldValue(typ, raw, scope); problem != "" {
+			warnAt(
+				ctx,
+				logger,
+				RuleInvalidValue,
+				argPos,
+				"directive for %s sets field %s to %s, which %s",
+				name,
+				key,
+				raw,
+				problem,
+			)
+		}
+	}
+
 	return instanceData{
-		Name:   name,
-		Fields: fieldMap,
+		Name:       name,
+		Fields:     fieldMap,
+		Attributes: attrs,
+		Pos:        pos,
 	}, true
 }
 
+// warnUnknownField warns that the directive for an instance sets a key that
+// is not a field, suggesting the closest field name if there is one.
+func warnUnknownField(
+	ctx context.Context,
+	logger *slog.Logger,
+	pos token.Position,
+	instance, key string,
+	fields []fieldInfo,
+) {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	suggestion := goenumr.Suggest(key, names)
+	if suggestion == "" {
+		warnAt(ctx, logger, RuleUnknownField, pos, "directive for %s sets unknown field %q", instance, key)
+		return
+	}
+	warnFixAt(
+		ctx,
+		logger,
+		RuleUnknownField,
+		pos,
+		Fix{Old: key, New: suggestion},
+		"directive for %s sets unknown field %q; did you mean %q?",
+		instance,
+		key,
+		suggestion,
+	)
+}
+
+// checkFieldValue describes why raw, the argument text a directive sets a
+// field of the given type to, cannot be a value of that type, or returns the
+// empty string if it may be. Only literals are checked against basic types,
+// including whether the type can represent them; a quoted value is a string
+// literal, whatever it contains. If scope is not nil, an identifier must be
+// declared in it or predeclared; other expressions are left to the type
+// checker.
+func checkFieldValue(typ, raw string, scope *types.Scope) string {
+	// Quotes only group Go syntax containing spaces, and are dropped from
+	// the generated code
+	value := raw
+	if unquoted, err := strconv.Unquote(raw); err == nil && raw[0] != '\'' {
+		value = unquoted
+	}
+	expr, err := parser.ParseExpr(value)
+	if err != nil {
+		return "is not a Go expression"
+	}
+	if ident, ok := ast.Unparen(expr).(*ast.Ident); ok && scope != nil {
+		if _, obj := scope.LookupParent(ident.Name, token.NoPos); obj == nil {
+			return "is undefined"
+		}
+	}
+
+	obj, _ := types.Universe.Lookup(typ).(*types.TypeName)
+	if obj == nil {
+		return ""
+	}
+	basic, ok := obj.Type().(*types.Basic)
+	if !ok {
+		return ""
+	}
+	if value != raw {
+		expr = &ast.BasicLit{Kind: token.STRING, Value: raw}
+	}
+
+	negative := false
+	if unary, isUnary := expr.(*ast.UnaryExpr); isUnary && (unary.Op == token.SUB || unary.Op == token.ADD) {
+		negative, expr = unary.Op == token.SUB, unary.X
+	}
+	var val constant.Value
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		val = constant.MakeFromLiteral(expr.Value, expr.Kind, 0)
+	case *ast.Ident:
+		if expr.Name != "true" && expr.Name != "false" {
+			return ""
+		}
+		val = constant.MakeBool(expr.Name == "true")
+	default:
+		return ""
+	}
+
+	if negative {
+		if kind := val.Kind(); kind != constant.Int && kind != constant.Float && kind != constant.Complex {
+			return "is not a valid " + typ
+		}
+		val = constant.UnaryOp(token.SUB, val, 0)
+	}
+
+	info := basic.Info()
+	size := fieldSizes.Sizeof(basic)
+	valid := false
+	switch {
+	case info&types.IsBoolean != 0:
+		valid = val.Kind() == constant.Bool
+	case info&types.IsInteger != 0:
+		val = constant.ToInt(val)
+		valid = val.Kind() == constant.Int && intFits(val, size, info&types.IsUnsigned != 0)
+	case info&types.IsFloat != 0:
+		val = constant.ToFloat(val)
+		valid = val.Kind() == constant.Float && floatFits(val, size)
+	case info&types.IsComplex != 0:
+		val = constant.ToComplex(val)
+		valid = val.Kind() == constant.Complex &&
+			floatFits(constant.Real(val), size/2) && floatFits(constant.Imag(val), size/2)
+	default:
+		return ""
+	}
+	if valid {
+		return ""
+	}
+	return "is not a valid " + typ
+}
+
+// fieldSizes are the sizes of basic types on the target architecture, which
+// bound the values of int, uint and uintptr fields.
+var fieldSizes = func() types.Sizes {
+	if sizes := types.SizesFor("gc", build.Default.GOARCH); sizes != nil {
+		return sizes
+	}
+	return types.SizesFor("gc", "amd64")
+}()
+
+// intFits reports whether the integer constant val is representable by an
+// integer type of size bytes.
+func intFits(val constant.Value, size int64, unsigned bool) bool {
+	bits := uint(8 * size)
+	if unsigned {
+		limit := constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+		return constant.Sign(val) >= 0 && constant.Compare(val, token.LSS, limit)
+	}
+	limit := constant.Shift(constant.MakeInt64(1), token.SHL, bits-1)
+	return constant.Compare(val, token.GEQ, constant.UnaryOp(token.SUB, limit, 0)) &&
+		constant.Compare(val, token.LSS, limit)
+}
+
+// floatFits reports whether the float constant val does not overflow a float
+// type of size bytes.
+func floatFits(val constant.Value, size int64) bool {
+	if size == 4 {
+		f, _ := constant.Float32Val(val)
+		return !math.IsInf(float64(f), 0)
+	}
+	f, _ := constant.Float64Val(val)
+	return !math.IsInf(f, 0)
+}
+
 // parseArgs parses the arguments from a directive string into a map.
-func parseArgs(ctx context.Context, logger *slog.Logger, args []string) map[string]string {
+func parseArgs(
+	ctx context.Context,
+	logger *slog.Logger,
+	pos token.Position,
+	args []directiveArg,
+) map[string]string {
 	values := make(map[string]string, len(args))
 	for _, arg := range args {
-		key, val, found := strings.Cut(arg, ":")
+		key, val, found := strings.Cut(arg.Text, ":")
 		if !found {
-			logger.LogAttrs(
+			warnAt(
 				ctx,
-				slog.LevelWarn,
-				"skipping directive argument without value",
-				slog.String("arg", arg),
+				logger,
+				RuleDirectiveSyntax,
+				argPosition(pos, arg.Offset),
+				"skipping directive argument without value: %q",
+				arg.Text,
 			)
 			continue // Skip arguments without a value
 		}
@@ -110,28 +330,59 @@ func parseArgs(ctx context.Context, logger *slog.Logger, args []string) map[stri
 	return values
 }
 
+// commentPosition returns the position of a comment, or the zero position if
+// no file set is available.
+func commentPosition(fset *token.FileSet, comment *ast.Comment) token.Position {
+	if fset == nil {
+		return token.Position{}
+	}
+	return fset.Position(comment.Slash)
+}
+
+// argPosition returns the position of the argument at byte offset within the
+// directive comment that starts at pos.
+func argPosition(pos token.Position, offset int) token.Position {
+	if pos.IsValid() {
+		pos.Offset += offset
+		pos.Column += offset
+	}
+	return pos
+}
+
+// directiveArg is an argument of a directive.
+type directiveArg struct {
+	Text string
+	// Offset is the byte offset of the argument in the directive comment.
+	Offset int
+}
+
 // splitArgs splits a string into arguments, respecting quotes.
 // It handles shell-style quoting (e.g., key:"value with spaces").
-func splitArgs(s string) []string {
-	var args []string
+// The offsets of the arguments are counted from offset, the position of s in
+// its comment.
+func splitArgs(s string, offset int) []directiveArg {
+	var args []directiveArg
 	var current strings.Builder
+	start := 0
 	inQuote := false
 
-	for _, r := range s {
+	for i, r := range s {
 		if r == '"' {
 			inQuote = !inQuote
-			current.WriteRune(r)
 		} else if unicode.IsSpace(r) && !inQuote {
 			if current.Len() > 0 {
-				args = append(args, current.String())
+				args = append(args, directiveArg{Text: current.String(), Offset: offset + start})
 				current.Reset()
 			}
-		} else {
-			current.WriteRune(r)
+			continue
+		}
+		if current.Len() == 0 {
+			start = i
 		}
+		current.WriteRune(r)
 	}
 	if current.Len() > 0 {
-		args = append(args, current.String())
+		args = append(args, directiveArg{Text: current.String(), Offset: offset + start})
 	}
 	return args
 }
@@ -2,6 +2,9 @@ package enumr
 
 import (
 	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
 	"log/slog"
 	"os"
 	"reflect"
@@ -11,20 +14,22 @@ import (
 func TestSplitArgs(t *testing.T) {
 	tests := []struct {
 		input    string
-		expected []string
+		expected []directiveArg
 	}{
-		{"key:value", []string{"key:value"}},
-		{"key:value flag:true", []string{"key:value", "flag:true"}},
-		{"key:\"quoted value\"", []string{"key:\"quoted value\""}},
-		{"key:\"quoted value\" flag:true", []string{"key:\"quoted value\"", "flag:true"}},
+		{"key:value", []directiveArg{{"key:value", 3}}},
+		{"key:value flag:true", []directiveArg{{"key:value", 3}, {"flag:true", 13}}},
+		{"key:\"quoted value\"", []directiveArg{{"key:\"quoted value\"", 3}}},
+		{"key:\"quoted value\"  flag:true", []directiveArg{{"key:\"quoted value\"", 3}, {"flag:true", 23}}},
 		{
 			"key:\"value with spaces\" another:val",
-			[]string{"key:\"value with spaces\"", "another:val"},
+			[]directiveArg{{"key:\"value with spaces\"", 3}, {"another:val", 27}},
 		},
+		{"Code:CC ode:CC", []directiveArg{{"Code:CC", 3}, {"ode:CC", 11}}},
+		{"Name:\"zürich\" x:1", []directiveArg{{"Name:\"zürich\"", 3}, {"x:1", 18}}},
 	}
 
 	for _, test := range tests {
-		result := splitArgs(test.input)
+		result := splitArgs(test.input, 3)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("splitArgs(%q) = %v; want %v", test.input, result, test.expected)
 		}
@@ -128,6 +133,20 @@ func TestParseDirectives(t *testing.T) {
 				"Desc": "\"two words\"",
 			},
 		},
+		{
+			name:      "Attribute",
+			directive: "//enumr:Item9 Code:I9 @alias=item_nine,nine",
+			wantCount: 1,
+			wantName:  "Item9",
+			wantFields: map[string]string{
+				"Code": "\"I9\"",
+			},
+		},
+		{
+			name:      "Attribute line",
+			directive: "//enumr:@parse=fold",
+			wantCount: 0,
+		},
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
@@ -138,7 +157,7 @@ func TestParseDirectives(t *testing.T) {
 				List: []*ast.Comment{{Text: tt.directive}},
 			}
 
-			instances := parseDirectives(t.Context(), logger, doc, fields)
+			instances := parseDirectives(t.Context(), logger, nil, doc, fields, nil)
 
 			if len(instances) != tt.wantCount {
 				t.Fatalf("got %d instances, want %d", len(instances), tt.wantCount)
@@ -156,3 +175,76 @@ func TestParseDirectives(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckFieldValue(t *testing.T) {
+	tests := []struct {
+		typ, value string
+		want       string
+	}{
+		{"int", "42", ""},
+		{"int", "-1", ""},
+		{"int", "1.0", ""},
+		{"int", "'a'", ""},
+		{"int", "1.5", "is not a valid int"},
+		{"int", "true", "is not a valid int"},
+		{"int", "MaxItems", ""},
+		{"uint8", "-1", "is not a valid uint8"},
+		{"uint8", "255", ""},
+		{"uint8", "300", "is not a valid uint8"},
+		{"int8", "-128", ""},
+		{"int8", "-129", "is not a valid int8"},
+		{"int8", "1e10", "is not a valid int8"},
+		{"int64", "9223372036854775808", "is not a valid int64"},
+		{"uint64", "18446744073709551615", ""},
+		{"float32", "1e38", ""},
+		{"float32", "1e39", "is not a valid float32"},
+		{"float64", "-1e309", "is not a valid float64"},
+		{"complex64", "1e39i", "is not a valid complex64"},
+		{"bool", "-true", "is not a valid bool"},
+		{"float64", "3", ""},
+		{"float64", "-2.5e3", ""},
+		{"complex128", "1i", ""},
+		{"bool", "false", ""},
+		{"bool", "1", "is not a valid bool"},
+		{"bool", "enabled", ""},
+		{"time.Duration", "1.5", ""},
+		{"[]string", `[]string{"a"}`, ""},
+		{"int", "1x", "is not a Go expression"},
+		{"[]string", "[]string{", "is not a Go expression"},
+		{"int8", `"str"`, "is not a valid int8"},
+		{"int", `"1"`, "is not a valid int"},
+		{"bool", "`true`", "is not a valid bool"},
+		{"[]string", `"[]string{\"a\"}"`, ""},
+	}
+
+	for _, tt := range tests {
+		if got := checkFieldValue(tt.typ, tt.value, nil); got != tt.want {
+			t.Errorf("checkFieldValue(%q, %q) = %q; want %q", tt.typ, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCheckFieldValueScope(t *testing.T) {
+	scope := types.NewScope(types.Universe, token.NoPos, token.NoPos, "payment")
+	scope.Insert(types.NewConst(token.NoPos, nil, "MaxItems", types.Typ[types.UntypedInt], constant.MakeInt64(10)))
+
+	tests := []struct {
+		typ, value string
+		want       string
+	}{
+		{"int", "MaxItems", ""},
+		{"int", "(MaxItems)", ""},
+		{"int", "-MaxItems", ""},
+		{"int", "MinItems", "is undefined"},
+		{"bool", "true", ""},
+		{"time.Duration", "Timeout", "is undefined"},
+		{"[]string", `"Names"`, "is undefined"},
+		{"int", "MinItems + 1", ""},
+	}
+
+	for _, tt := range tests {
+		if got := checkFieldValue(tt.typ, tt.value, scope); got != tt.want {
+			t.Errorf("checkFieldValue(%q, %q) = %q; want %q", tt.typ, tt.value, got, tt.want)
+		}
+	}
+}
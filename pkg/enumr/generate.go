@@ -4,6 +4,8 @@ package enumr
 import (
 	"context"
 	"fmt"
+	"go/format"
+	"go/token"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -12,6 +14,10 @@ import (
 )
 
 // Generator handles the enum generation process.
+//
+// A Generator is safe for concurrent use by multiple goroutines. Generate
+// only reads the package it is given and its lock files, so packages may also
+// be shared between calls.
 type Generator struct {
 	Logger *slog.Logger
 }
@@ -21,16 +27,130 @@ func NewGenerator(logger *slog.Logger) *Generator {
 	return &Generator{Logger: logger}
 }
 
+// Options controls how enum instances are resolved and rendered.
+type Options struct {
+	// Format is the casing format applied to instance names when no
+	// MarshalField is set (e.g. "snake_case").
+	Format string
+	// MarshalField is the struct field used for String/MarshalText.
+	MarshalField string
+	// IncludeZero allows the empty string to be parsed as the zero value.
+	IncludeZero bool
+	// SQL generates Value and Scan methods implementing driver.Valuer and
+	// sql.Scanner.
+	SQL bool
+	// Ordinal generates Ordinal, Compare, Next and Prev methods and the
+	// <Type>Count, <Type>FromOrdinal, <Type>Min and <Type>Max helpers.
+	Ordinal bool
+	// Set generates a <Type>Set type holding a set of values as a bitset.
+	// It implies Ordinal.
+	Set bool
+	// Map generates a generic <Type>Map type mapping values to V, stored as an
+	// array. It implies Ordinal.
+	Map bool
+	// Match generates a generic <Type>Cases struct with a handler per value
+	// and Match<Type> functions that call the handler of a value.
+	Match bool
+	// ExcludeDeprecated leaves deprecated instances out of <Type>Values.
+	// They are still accepted by Parse<Type>.
+	ExcludeDeprecated bool
+	// Runtime makes generated code return *enumr.UnknownValueError, with a
+	// suggestion of the closest valid value, and errors matching
+	// enumr.ErrUnknownValue and enumr.ErrNoHandler. The generated code then
+	// imports github.com/jmfrees/go-enumr, which the module must require;
+	// without Runtime it only imports the standard library and the packages
+	// of the parse modes used.
+	Runtime bool
+	// Parse is the default parse mode of types without an @parse attribute:
+	// a comma-separated list of "exact", "fold", "trim", "nfc" and "nfkc".
+	Parse string
+	// Lock verifies wire values against <type>.enumr.lock files in the
+	// package directory, failing if a locked value would change.
+	Lock bool
+	// UpdateLock accepts changed or removed wire values and rewrites the lock.
+	UpdateLock bool
+	// SkipTypeCheck disables type-checking the generated source against the
+	// package before it is returned.
+	SkipTypeCheck bool
+	// Split generates each type into its own file instead of one file per
+	// package. Generate produces the source of a single file; OutputFiles and
+	// OutputUpToDate use Split to determine which files there are.
+	Split bool
+	// Header is placed above the "Code generated" line of generated files,
+	// e.g. a license block. Lines that are not already comments are turned
+	// into line comments.
+	Header string
+}
+
 // Generate processes a single Go file to find and generate enums for the given type.
-// It returns the generated source code as a byte slice.
+// It returns the generated source code as a byte slice. With Options.Lock or
+// Options.UpdateLock it also returns the lock files that need updating, which
+// the caller writes once the source has been written.
 func (g *Generator) Generate(
 	ctx context.Context,
 	pkg *packages.Package,
 	typeNames []string,
-	nameFormat string,
-	marshalField string,
-	includeZero bool,
-) ([]byte, error) {
+	opts Options,
+) ([]byte, []LockUpdate, error) {
+	enums, err := g.resolveEnums(ctx, pkg, typeNames, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(enums) == 0 {
+		return nil, nil, nil
+	}
+
+	g.Logger.LogAttrs(
+		ctx,
+		slog.LevelDebug,
+		"Generating enum source",
+		slog.String("package", pkg.Name),
+		slog.Any("types", typeNames),
+	)
+
+	// Generate the enum code for the type and its instances
+	source, err := renderSource(enumData{
+		Header:      formatHeader(opts.Header),
+		PackageName: pkg.Name,
+		InputHash:   InputHash(pkg.Fset, pkg.Syntax, typeNames, opts),
+		Enums:       enums,
+	})
+	if err != nil {
+		return nil, nil, errorAt(RuleGenerate, token.Position{}, "error generating enum source: %v", err)
+	}
+
+	// Catch directive values that produce invalid Go before anything is written
+	if !opts.SkipTypeCheck {
+		if err = typeCheck(pkg, enums, source); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Format the source so that it is stable under gofmt
+	formatted, err := format.Source(source)
+	if err != nil {
+		return nil, nil, errorAt(RuleGenerate, token.Position{}, "error formatting enum source: %v", err)
+	}
+	source = formatted
+
+	var locks []LockUpdate
+	if opts.Lock || opts.UpdateLock {
+		if locks, err = checkLocks(pkg.Dir, enums, opts.UpdateLock); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return source, locks, nil
+}
+
+// resolveEnums builds the enum model for each of the given types.
+func (g *Generator) resolveEnums(
+	ctx context.Context,
+	pkg *packages.Package,
+	typeNames []string,
+	opts Options,
+) ([]enumInfo, error) {
 	var enums []enumInfo
 
 	for _, typeName := range typeNames {
@@ -46,49 +166,68 @@ func (g *Generator) Generate(
 			return nil, err
 		}
 
+		attrs := parseTypeAttributes(ctx, g.Logger, pkg.Fset, typeSpec.Doc)
+		parse, err := resolveParseMode(attrs, opts)
+		if err != nil {
+			return nil, err
+		}
+
 		// Validate that if marshalField is specified, all instances have it
-		if marshalField != "" {
+		if opts.MarshalField != "" {
 			for _, instance := range resolution.Instances {
-				if _, ok := instance.Fields[marshalField]; !ok {
-					return nil, fmt.Errorf(
+				if _, ok := instance.Fields[opts.MarshalField]; !ok {
+					return nil, errorAt(
+						RuleMissingMarshalField,
+						instance.Pos,
 						"instance %s does not have field %q",
 						instance.Name,
-						marshalField,
+						opts.MarshalField,
 					)
 				}
 			}
 		}
 
-		enums = append(enums, enumInfo{
-			TypeName:     typeName,
-			Instances:    resolution.Instances,
-			CaseFormat:   nameFormat,
-			GenerateVars: resolution.GenerateVars,
-			IncludeZero:  includeZero,
-			MarshalField: marshalField,
-			StructFields: typeSpec.Fields,
-		})
-	}
-
-	if len(enums) == 0 {
-		return nil, nil
-	}
-
-	g.Logger.LogAttrs(
-		ctx,
-		slog.LevelDebug,
-		"Generating enum source",
-		slog.String("package", pkg.Name),
-		slog.Any("types", typeNames),
-	)
-
-	// Generate the enum code for the type and its instances
-	source, err := generateEnumSource(pkg.Name, enums)
-	if err != nil {
-		return nil, fmt.Errorf("error generating enum source: %w", err)
+		enum := enumInfo{
+			TypeName:          typeName,
+			Instances:         resolution.Instances,
+			CaseFormat:        opts.Format,
+			GenerateVars:      resolution.GenerateVars,
+			IncludeZero:       opts.IncludeZero,
+			ParseMode:         parse,
+			SQL:               opts.SQL,
+			Ordinal:           opts.Ordinal || opts.Set || opts.Map,
+			Set:               opts.Set,
+			Map:               opts.Map,
+			Match:             opts.Match,
+			MarshalField:      opts.MarshalField,
+			ExcludeDeprecated: opts.ExcludeDeprecated,
+			Runtime:           opts.Runtime,
+			StructFields:      typeSpec.Fields,
+		}
+		if err = enum.resolveAliases(); err != nil {
+			return nil, err
+		}
+		if err = enum.resolveDefault(); err != nil {
+			return nil, err
+		}
+		if err = enum.resolveUnknownField(attrs); err != nil {
+			return nil, err
+		}
+		if err = enum.resolveIDs(attrs); err != nil {
+			return nil, err
+		}
+		if err = enum.resolveOrder(); err != nil {
+			return nil, err
+		}
+		if err = enum.checkSetValues(); err != nil {
+			return nil, err
+		}
+		_, enum.Wrap = attrs["wrap"]
+		enum.resolveDeprecations()
+		enums = append(enums, enum)
 	}
 
-	return source, nil
+	return enums, nil
 }
 
 // GetOutputFilename determines the output filename based on the directory, type name, and output flag.
@@ -112,19 +251,25 @@ func (g *Generator) resolveInstances(
 	pkg *packages.Package,
 	typeSpec *typeSpec,
 ) (instanceResolution, error) {
-	// 1. Try Directives
-	instances := parseDirectives(ctx, g.Logger, typeSpec.Doc, typeSpec.Fields)
+	// 1. Try Directives. Undefined identifiers are left to the type check,
+	// which names the instance they belong to.
+	instances := parseDirectives(ctx, g.Logger, pkg.Fset, typeSpec.Doc, typeSpec.Fields, nil)
 	if len(instances) > 0 {
 		return instanceResolution{Instances: instances, GenerateVars: true}, nil
 	}
 
 	// 2. Fallback to Scanning
 	instances = collectInstances(pkg, typeSpec.TypeSpec.Name.Name, typeSpec.Fields)
+	for i := range instances {
+		instances[i].Attributes = parseAttributeLines(ctx, g.Logger, pkg.Fset, instances[i].Doc, instanceAttributes)
+	}
 	if len(instances) > 0 {
 		return instanceResolution{Instances: instances, GenerateVars: false}, nil
 	}
 
-	return instanceResolution{}, fmt.Errorf(
+	return instanceResolution{}, errorAt(
+		RuleNoInstances,
+		pkg.Fset.Position(typeSpec.TypeSpec.Name.Pos()),
 		"failed to find any instances of %s",
 		typeSpec.TypeSpec.Name.Name,
 	)
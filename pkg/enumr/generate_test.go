@@ -1,8 +1,17 @@
 package enumr
 
 import (
+	"bytes"
+	"go/format"
+	"log/slog"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+
+	"golang.org/x/tools/go/packages"
 )
 
 func TestGetOutputFilename(t *testing.T) {
@@ -54,3 +63,146 @@ func TestGetOutputFilename(t *testing.T) {
 		})
 	}
 }
+
+func loadTestPackage(t *testing.T, dir string) *packages.Package {
+	t.Helper()
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadSyntax,
+		Dir:  filepath.Join("testdata", dir),
+	}, ".")
+	if err != nil {
+		t.Fatalf("failed to load package: %v", err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("loaded %d packages, want 1", len(pkgs))
+	}
+	return pkgs[0]
+}
+
+func TestGeneratorConcurrentUse(t *testing.T) {
+	pkg := loadTestPackage(t, "payment")
+	generator := NewGenerator(slog.New(slog.DiscardHandler))
+	typeNames := []string{"Method", "Status"}
+	opts := Options{Format: "snake_case"}
+
+	want, _, err := generator.Generate(t.Context(), pkg, typeNames, opts)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	const workers = 8
+	results := make([][]byte, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _, errs[i] = generator.Generate(t.Context(), pkg, typeNames, opts)
+		}()
+	}
+	wg.Wait()
+
+	for i := range workers {
+		if errs[i] != nil {
+			t.Errorf("concurrent Generate %d failed: %v", i, errs[i])
+			continue
+		}
+		if !bytes.Equal(results[i], want) {
+			t.Errorf("concurrent Generate %d produced different output:\n%s", i, results[i])
+		}
+	}
+}
+
+func TestGenerateFormatted(t *testing.T) {
+	pkg := loadTestPackage(t, "payment")
+	generator := NewGenerator(slog.New(slog.DiscardHandler))
+
+	source, _, err := generator.Generate(t.Context(), pkg, []string{"Method", "Status"}, Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	formatted, err := format.Source(source)
+	if err != nil {
+		t.Fatalf("format.Source failed: %v", err)
+	}
+	if !bytes.Equal(source, formatted) {
+		t.Errorf("generated code is not gofmt'd:\n%s", source)
+	}
+}
+
+func TestGenerateTypeCheck(t *testing.T) {
+	pkg := loadTestPackage(t, "badvalue")
+	generator := NewGenerator(slog.New(slog.DiscardHandler))
+
+	source, _, err := generator.Generate(t.Context(), pkg, []string{"Size"}, Options{})
+	if err == nil {
+		t.Fatalf("Generate succeeded, want type-check error:\n%s", source)
+	}
+
+	diags := Diagnostics(err)
+	if len(diags) != 1 {
+		t.Fatalf("got %d diagnostics, want 1: %v", len(diags), err)
+	}
+	diag := diags[0]
+	if diag.Rule != RuleTypeCheck {
+		t.Errorf("Rule = %q, want %q", diag.Rule, RuleTypeCheck)
+	}
+	if filepath.Base(diag.Pos.Filename) != "badvalue.go" || diag.Pos.Line != 4 {
+		t.Errorf("Pos = %v, want badvalue.go:4", diag.Pos)
+	}
+	if !strings.Contains(diag.Message, "Large") {
+		t.Errorf("Message = %q, want it to name the instance", diag.Message)
+	}
+
+	if _, _, err = generator.Generate(t.Context(), pkg, []string{"Size"}, Options{SkipTypeCheck: true}); err != nil {
+		t.Errorf("Generate with SkipTypeCheck failed: %v", err)
+	}
+}
+
+func TestGenerateNormalize(t *testing.T) {
+	pkg := loadTestPackage(t, "normalize")
+	generator := NewGenerator(slog.New(slog.DiscardHandler))
+
+	source, _, err := generator.Generate(t.Context(), pkg, []string{"City"}, Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !bytes.Contains(source, []byte(`"golang.org/x/text/unicode/norm"`)) {
+		t.Errorf("generated code does not import norm:\n%s", source)
+	}
+}
+
+func TestGenerateMissingModule(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/city\n\ngo 1.24\n",
+		"city.go": "package city\n\n// enumr:@parse=nfkc\n// enumr:Zurich\n// enumr:Geneva\ntype City struct{ code int }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Keep the go command from looking up the missing modules
+	t.Setenv("GOPROXY", "off")
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadSyntax, Dir: dir}, ".")
+	if err != nil || len(pkgs) != 1 {
+		t.Fatalf("failed to load package: %v", err)
+	}
+	generator := NewGenerator(slog.New(slog.DiscardHandler))
+
+	_, _, err = generator.Generate(t.Context(), pkgs[0], []string{"City"}, Options{Runtime: true})
+	var messages []string
+	for _, diag := range Diagnostics(err) {
+		messages = append(messages, diag.Message)
+	}
+	for _, module := range []string{"golang.org/x/text", modulePath} {
+		if !slices.ContainsFunc(messages, func(m string) bool {
+			return strings.Contains(m, `"go get `+module+`"`)
+		}) {
+			t.Errorf("diagnostics %q do not suggest go get %s", messages, module)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package enumr
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"hash"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// generatedHeader is the first line of every generated file.
+	generatedHeader = "// Code generated by enumr. DO NOT EDIT."
+	// hashHeaderPrefix starts the header line that records the input hash.
+	hashHeaderPrefix = "// enumr-hash: "
+	// modulePath is the path of the module providing the generator.
+	modulePath = "github.com/jmfrees/go-enumr"
+)
+
+// InputHash returns a hash of everything that determines the code generated
+// for the given types: their declarations and directives, the var
+// declarations of their instances, the options, the template and the version
+// of enumr. Files previously generated by enumr are ignored, as are types not
+// declared in the files.
+func InputHash(fset *token.FileSet, files []*ast.File, typeNames []string, opts Options) string {
+	sources := sourceFiles(files)
+	slices.SortFunc(sources, func(a, b *ast.File) int {
+		return strings.Compare(fset.Position(a.Package).Filename, fset.Position(b.Package).Filename)
+	})
+
+	h := sha256.New()
+	fmt.Fprintf(h, "version %s\n", toolVersion())
+	fmt.Fprintf(h, "template %s\n", enumTemplate)
+	fmt.Fprintf(h, "options %+v\n", opts)
+	fmt.Fprintf(h, "types %s\n", strings.Join(declaredTypes(sources, typeNames), ","))
+
+	for _, file := range sources {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || !declaresInputs(genDecl, typeNames) {
+				continue
+			}
+			hashComments(h, genDecl.Doc)
+			for _, spec := range genDecl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					hashComments(h, spec.Doc)
+				case *ast.ValueSpec:
+					hashComments(h, spec.Doc)
+				}
+			}
+			_ = printer.Fprint(h, fset, genDecl)
+			fmt.Fprintln(h)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// OutputUpToDate reports whether the files generated for the package in dir
+// were produced from the current inputs, using only a syntactic scan of the Go
+// files in dir. The output files are determined as by OutputFiles from the
+// types the package declares. A missing output file or one without a recorded
+// hash is never up to date.
+func OutputUpToDate(dir, output string, typeNames []string, opts Options) (bool, error) {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return false, err
+	}
+
+	sources := sourceFiles(files)
+	declared := declaredTypes(sources, typeNames)
+	if len(declared) == 0 {
+		return false, nil
+	}
+
+	for _, out := range OutputFiles(dir, sources[0].Name.Name, declared, output, opts) {
+		recorded, err := readInputHash(out.Path)
+		if errors.Is(err, os.ErrNotExist) || recorded == "" {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if InputHash(fset, files, out.Types, opts) != recorded {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// sourceFiles returns the files that were not generated by enumr.
+func sourceFiles(files []*ast.File) []*ast.File {
+	sources := make([]*ast.File, 0, len(files))
+	for _, file := range files {
+		if !IsGeneratedFile(file) {
+			sources = append(sources, file)
+		}
+	}
+	return sources
+}
+
+// declaredTypes returns the type names that are declared in the files, in
+// the order they were given.
+func declaredTypes(files []*ast.File, typeNames []string) []string {
+	declared := make(map[string]bool)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				declared[spec.(*ast.TypeSpec).Name.Name] = true
+			}
+		}
+	}
+
+	var names []string
+	for _, name := range typeNames {
+		if declared[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseDir parses the non-test Go files in dir that match the current build
+// context.
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if match, err := build.Default.MatchFile(dir, name); err != nil || !match {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// readInputHash returns the input hash recorded in the header of a generated file.
+func readInputHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if hash, ok := strings.CutPrefix(line, hashHeaderPrefix); ok {
+			return strings.TrimSpace(hash), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// declaresInputs reports whether the declaration declares one of the types,
+// or a var initialized with a composite literal of one of them.
+func declaresInputs(genDecl *ast.GenDecl, typeNames []string) bool {
+	for _, spec := range genDecl.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			if slices.Contains(typeNames, spec.Name.Name) {
+				return true
+			}
+		case *ast.ValueSpec:
+			for _, value := range spec.Values {
+				lit, ok := value.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				if ident, ok := lit.Type.(*ast.Ident); ok && slices.Contains(typeNames, ident.Name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func hashComments(h hash.Hash, doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		fmt.Fprintln(h, comment.Text)
+	}
+}
+
+// IsGeneratedFile reports whether the file was generated by enumr, judging by
+// its "Code generated" header. The file must be parsed with comments.
+func IsGeneratedFile(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if comment.Text == generatedHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toolVersion returns the version of the enumr module in the running binary.
+// Development builds report an empty version: their pseudo-versions change
+// with every commit and with local edits, and would otherwise make the hash
+// of generated files differ between checkouts of the same source.
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path == modulePath {
+		return releaseVersion(info.Main.Version)
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return releaseVersion(dep.Version)
+		}
+	}
+	return ""
+}
+
+// releaseVersion returns version if it is a tagged release, and the empty
+// string for "(devel)", pseudo-versions and builds from a modified checkout.
+func releaseVersion(version string) string {
+	if !semver.IsValid(version) || module.IsPseudoVersion(version) || semver.Build(version) != "" {
+		return ""
+	}
+	return version
+}
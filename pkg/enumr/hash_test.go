@@ -0,0 +1,174 @@
+package enumr
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const hashTestSource = `package payment
+
+//enumr:CreditCard Code:CC
+//enumr:PayPal Code:PP
+type Method struct {
+	Code string
+}
+
+type Status struct {
+	ID int
+}
+
+var (
+	Active   = Status{1}
+	Inactive = Status{2}
+)
+
+func helper() int { return 1 }
+`
+
+func parseHashTestSource(t *testing.T, src string) (*token.FileSet, []*ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "payment.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+	return fset, []*ast.File{file}
+}
+
+func TestInputHash(t *testing.T) {
+	types := []string{"Method", "Status"}
+	fset, files := parseHashTestSource(t, hashTestSource)
+	base := InputHash(fset, files, types, Options{})
+
+	tests := []struct {
+		name    string
+		src     string
+		types   []string
+		opts    Options
+		changed bool
+	}{
+		{
+			name:  "Unrelated function",
+			src:   strings.Replace(hashTestSource, "return 1", "return 2", 1),
+			types: types,
+		},
+		{
+			name:  "Unrelated comment",
+			src:   hashTestSource + "\n// trailing comment\n",
+			types: types,
+		},
+		{
+			name:    "Directive",
+			src:     strings.Replace(hashTestSource, "Code:PP", "Code:PX", 1),
+			types:   types,
+			changed: true,
+		},
+		{
+			name:    "Field",
+			src:     strings.Replace(hashTestSource, "Code string", "Code string\n\tDesc string", 1),
+			types:   types,
+			changed: true,
+		},
+		{
+			name:    "Var",
+			src:     strings.Replace(hashTestSource, "Status{2}", "Status{3}", 1),
+			types:   types,
+			changed: true,
+		},
+		{
+			name:    "Options",
+			src:     hashTestSource,
+			types:   types,
+			opts:    Options{Format: "snake_case"},
+			changed: true,
+		},
+		{
+			name:    "Types",
+			src:     hashTestSource,
+			types:   []string{"Method"},
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, files := parseHashTestSource(t, tt.src)
+			got := InputHash(fset, files, tt.types, tt.opts)
+			if changed := got != base; changed != tt.changed {
+				t.Errorf("hash changed = %v; want %v", changed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestOutputUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	types := []string{"Method"}
+	source := filepath.Join(dir, "payment.go")
+	output := filepath.Join(dir, "method_enum.go")
+	types = append(types, "Undeclared")
+
+	if err := os.WriteFile(source, []byte(hashTestSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	upToDate, err := OutputUpToDate(dir, "", types, Options{})
+	if err != nil || upToDate {
+		t.Fatalf("OutputUpToDate() without output = %v, %v; want false, nil", upToDate, err)
+	}
+
+	fset, files := parseHashTestSource(t, hashTestSource)
+	generated, err := renderSource(enumData{
+		PackageName: "payment",
+		InputHash:   InputHash(fset, files, types, Options{}),
+		Enums: []enumInfo{{
+			TypeName:  "Method",
+			Instances: []instanceData{{Name: "CreditCard"}, {Name: "PayPal"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+	if err = os.WriteFile(output, generated, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	upToDate, err = OutputUpToDate(dir, "", types, Options{})
+	if err != nil || !upToDate {
+		t.Fatalf("OutputUpToDate() after generation = %v, %v; want true, nil", upToDate, err)
+	}
+
+	changed := strings.Replace(hashTestSource, "Code:PP", "Code:PX", 1)
+	if err = os.WriteFile(source, []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	upToDate, err = OutputUpToDate(dir, "", types, Options{})
+	if err != nil || upToDate {
+		t.Fatalf("OutputUpToDate() after change = %v, %v; want false, nil", upToDate, err)
+	}
+}
+
+func TestReleaseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "v1.2.3", want: "v1.2.3"},
+		{version: "v1.3.0-rc.1", want: "v1.3.0-rc.1"},
+		{version: "(devel)", want: ""},
+		{version: "", want: ""},
+		{version: "v0.0.0-20261018120000-0123456789ab", want: ""},
+		{version: "v1.2.4-0.20261018120000-0123456789ab+dirty", want: ""},
+		{version: "v1.2.3+dirty", want: ""},
+	}
+	for _, tt := range tests {
+		if got := releaseVersion(tt.version); got != tt.want {
+			t.Errorf("releaseVersion(%q) = %q; want %q", tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package enumr
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// resolveIDs sets the ID of each instance from its @id attribute. IDs are
+// optional, but if one instance has an @id every instance needs a distinct,
+// non-negative one. Gaps between IDs are allowed, as IDs of removed instances
+// should not be reused, unless the type sets "@ids=contiguous".
+func (e *enumInfo) resolveIDs(attrs attributes) error {
+	hasID := func(instance instanceData) bool {
+		_, ok := instance.Attributes["id"]
+		return ok
+	}
+	if !slices.ContainsFunc(e.Instances, hasID) {
+		if attr, ok := attrs["ids"]; ok {
+			return errorAt(RuleInvalidAttribute, attr.Pos, "@ids is set, but no instance of %s has an @id", e.TypeName)
+		}
+		return nil
+	}
+
+	owners := make(map[int]string, len(e.Instances))
+	for i := range e.Instances {
+		instance := &e.Instances[i]
+		attr, ok := instance.Attributes["id"]
+		if !ok {
+			return errorAt(
+				RuleInvalidAttribute,
+				instance.Pos,
+				"instance %s has no @id, but other instances of %s do",
+				instance.Name,
+				e.TypeName,
+			)
+		}
+		id, err := strconv.Atoi(attr.Value)
+		if err != nil || id < 0 {
+			return errorAt(RuleInvalidAttribute, attr.Pos, "@id of %s is not a non-negative integer: %q", instance.Name, attr.Value)
+		}
+		if owner, taken := owners[id]; taken {
+			return errorAt(RuleDuplicateID, attr.Pos, "@id %d of %s is already used by %s", id, instance.Name, owner)
+		}
+		owners[id] = instance.Name
+		instance.ID = id
+	}
+	e.IDs = true
+
+	attr, ok := attrs["ids"]
+	switch {
+	case !ok || attr.Value == "sparse":
+	case attr.Value == "contiguous":
+		ids := slices.Sorted(maps.Keys(owners))
+		for i := 1; i < len(ids); i++ {
+			if ids[i] != ids[i-1]+1 {
+				return errorAt(
+					RuleInvalidAttribute,
+					attr.Pos,
+					"IDs of %s are not contiguous: no instance has ID %d",
+					e.TypeName,
+					ids[i-1]+1,
+				)
+			}
+		}
+	default:
+		return errorAt(RuleInvalidAttribute, attr.Pos, "unknown @ids policy %q (want sparse or contiguous)", attr.Value)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package enumr
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestResolveIDs(t *testing.T) {
+	pos := token.Position{Filename: "method.go", Line: 6, Column: 18}
+	id := func(value string) attributes {
+		return attributes{"id": {Value: value, Pos: pos}}
+	}
+	policy := func(value string) attributes {
+		return attributes{"ids": {Value: value, Pos: pos}}
+	}
+	tests := []struct {
+		name      string
+		attrs     attributes
+		instances []instanceData
+		expected  []int
+		wantRule  string
+	}{
+		{
+			name:      "No IDs",
+			instances: []instanceData{{Name: "Card"}, {Name: "Cash"}},
+		},
+		{
+			name:      "Sparse",
+			instances: []instanceData{{Name: "Card", Attributes: id("7")}, {Name: "Cash", Attributes: id("2")}},
+			expected:  []int{7, 2},
+		},
+		{
+			name:      "Contiguous",
+			attrs:     policy("contiguous"),
+			instances: []instanceData{{Name: "Card", Attributes: id("1")}, {Name: "Cash", Attributes: id("0")}},
+			expected:  []int{1, 0},
+		},
+		{
+			name:      "Gap",
+			attrs:     policy("contiguous"),
+			instances: []instanceData{{Name: "Card", Attributes: id("1")}, {Name: "Cash", Attributes: id("3")}},
+			wantRule:  RuleInvalidAttribute,
+		},
+		{
+			name:      "Unknown policy",
+			attrs:     policy("dense"),
+			instances: []instanceData{{Name: "Card", Attributes: id("1")}},
+			wantRule:  RuleInvalidAttribute,
+		},
+		{
+			name:      "Policy without IDs",
+			attrs:     policy("sparse"),
+			instances: []instanceData{{Name: "Card"}},
+			wantRule:  RuleInvalidAttribute,
+		},
+		{
+			name:      "Missing",
+			instances: []instanceData{{Name: "Card", Attributes: id("1")}, {Name: "Cash", Pos: pos}},
+			wantRule:  RuleInvalidAttribute,
+		},
+		{
+			name:      "Negative",
+			instances: []instanceData{{Name: "Card", Attributes: id("-1")}},
+			wantRule:  RuleInvalidAttribute,
+		},
+		{
+			name:      "Reused",
+			instances: []instanceData{{Name: "Card", Attributes: id("1")}, {Name: "Cash", Attributes: id("1")}},
+			wantRule:  RuleDuplicateID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enum := enumInfo{TypeName: "Method", Instances: tt.instances}
+			err := enum.resolveIDs(tt.attrs)
+			if tt.wantRule != "" {
+				diags := Diagnostics(err)
+				if len(diags) != 1 || diags[0].Rule != tt.wantRule || diags[0].Pos != pos {
+					t.Errorf("resolveIDs() error = %v; want %s at %v", err, tt.wantRule, pos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveIDs() failed: %v", err)
+			}
+			if enum.IDs != (tt.expected != nil) {
+				t.Errorf("resolveIDs() IDs = %v; want %v", enum.IDs, tt.expected != nil)
+			}
+			for i, want := range tt.expected {
+				if got := enum.Instances[i].ID; got != want {
+					t.Errorf("%s.ID = %d; want %d", enum.Instances[i].Name, got, want)
+				}
+			}
+		})
+	}
+}
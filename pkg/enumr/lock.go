@@ -0,0 +1,285 @@
+package enumr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// Lock records the wire values of an enum type. It is committed alongside the
+// source so that removing or changing a wire value is an explicit, reviewable
+// change rather than a silent one.
+type Lock struct {
+	Type    string      `json:"type"`
+	Entries []LockEntry `json:"entries"`
+	// NextID is the ID assigned to the next new instance. IDs of removed
+	// instances are never reused.
+	NextID int `json:"next_id"`
+}
+
+// LockEntry records the wire value and stable ID of a single instance.
+type LockEntry struct {
+	Name string `json:"name"`
+	Wire string `json:"wire"`
+	ID   int    `json:"id"`
+}
+
+// LockFilename returns the path of the lock file for a type in the given directory.
+func LockFilename(dir, typeName string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.enumr.lock", toSnakeCase(typeName)))
+}
+
+// ReadLock reads a lock file. The returned error wraps os.ErrNotExist if
+// the file does not exist.
+func ReadLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lock Lock
+	if err = json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to decode lock file %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// WriteFile writes the lock as indented JSON.
+func (l *Lock) WriteFile(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lock file %s: %w", path, err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Check reports every locked wire value that is missing from, or changed in,
+// the given type, and every explicit ID that differs from the locked ID of its
+// instance or was locked for another instance. Each locked entry is checked
+// against the instance of the same name, so that instances swapping wire
+// values are reported rather than passing because every value still exists.
+// An entry whose name is gone and whose wire value is used by an instance that
+// is not locked was renamed, which is allowed.
+func (l *Lock) Check(t TypeSnapshot) error {
+	byName := make(map[string]InstanceSnapshot, len(t.Instances))
+	byWire := make(map[string]InstanceSnapshot, len(t.Instances))
+	for _, instance := range t.Instances {
+		byName[instance.Name] = instance
+		byWire[instance.Wire] = instance
+	}
+
+	locked := make(map[string]bool, len(l.Entries))
+	for _, entry := range l.Entries {
+		locked[entry.Name] = true
+	}
+
+	var errs []error
+	for _, entry := range l.Entries {
+		instance, named := byName[entry.Name]
+		owner, owned := byWire[entry.Wire]
+		switch {
+		case named && instance.Wire == entry.Wire:
+		case named && owned:
+			errs = append(errs, fmt.Errorf(
+				"%s.%s: locked wire value %q changed to %q and is now used by %s",
+				l.Type,
+				entry.Name,
+				entry.Wire,
+				instance.Wire,
+				owner.Name,
+			))
+		case named:
+			errs = append(errs, fmt.Errorf(
+				"%s.%s: locked wire value %q changed to %q",
+				l.Type,
+				entry.Name,
+				entry.Wire,
+				instance.Wire,
+			))
+		case owned && locked[owner.Name]:
+			errs = append(errs, fmt.Errorf(
+				"%s.%s: locked wire value %q is now used by %s",
+				l.Type,
+				entry.Name,
+				entry.Wire,
+				owner.Name,
+			))
+		case !owned:
+			errs = append(errs, fmt.Errorf(
+				"%s.%s: locked wire value %q was removed",
+				l.Type,
+				entry.Name,
+				entry.Wire,
+			))
+		}
+	}
+	return errors.Join(append(errs, l.checkIDs(t)...)...)
+}
+
+// checkIDs reports explicit IDs that differ from the locked ID of their
+// instance, matched by wire value or failing that by name, or that were locked
+// for another instance.
+func (l *Lock) checkIDs(t TypeSnapshot) []error {
+	var errs []error
+	for _, instance := range t.Instances {
+		if instance.ID == nil {
+			continue
+		}
+		entry, ok := l.entry(instance)
+		if ok && entry.ID != *instance.ID {
+			errs = append(errs, fmt.Errorf(
+				"%s.%s: locked ID %d changed to %d",
+				l.Type,
+				instance.Name,
+				entry.ID,
+				*instance.ID,
+			))
+			continue
+		}
+		for _, other := range l.Entries {
+			if other.ID == *instance.ID && (!ok || other != entry) {
+				errs = append(errs, fmt.Errorf(
+					"%s.%s: ID %d is locked for %s",
+					l.Type,
+					instance.Name,
+					*instance.ID,
+					other.Name,
+				))
+			}
+		}
+	}
+	return errs
+}
+
+// entry returns the locked entry of an instance, matched by wire value or
+// failing that by name.
+func (l *Lock) entry(instance InstanceSnapshot) (LockEntry, bool) {
+	for _, entry := range l.Entries {
+		if entry.Wire == instance.Wire {
+			return entry, true
+		}
+	}
+	for _, entry := range l.Entries {
+		if entry.Name == instance.Name {
+			return entry, true
+		}
+	}
+	return LockEntry{}, false
+}
+
+// Update returns a lock describing the given type. Instances with an explicit
+// ID are locked with it. Other instances keep the ID of the entry with the
+// same wire value, or failing that the same name, so that renames preserve
+// IDs. New instances are assigned the next unused ID.
+func (l *Lock) Update(t TypeSnapshot) *Lock {
+	updated := &Lock{Type: t.Name, NextID: l.NextID}
+	byWire := make(map[string]LockEntry, len(l.Entries))
+	byName := make(map[string]LockEntry, len(l.Entries))
+	for _, entry := range l.Entries {
+		byWire[entry.Wire] = entry
+		byName[entry.Name] = entry
+		updated.NextID = max(updated.NextID, entry.ID+1)
+	}
+
+	used := make(map[int]bool, len(t.Instances))
+	for _, instance := range t.Instances {
+		if instance.ID != nil {
+			used[*instance.ID] = true
+			updated.NextID = max(updated.NextID, *instance.ID+1)
+		}
+	}
+	for _, instance := range t.Instances {
+		if instance.ID != nil {
+			updated.Entries = append(updated.Entries, LockEntry{
+				Name: instance.Name,
+				Wire: instance.Wire,
+				ID:   *instance.ID,
+			})
+			continue
+		}
+		entry, ok := byWire[instance.Wire]
+		if !ok || used[entry.ID] {
+			entry, ok = byName[instance.Name]
+		}
+		id := entry.ID
+		if !ok || used[id] {
+			id = updated.NextID
+			updated.NextID++
+		}
+		used[id] = true
+		updated.Entries = append(updated.Entries, LockEntry{
+			Name: instance.Name,
+			Wire: instance.Wire,
+			ID:   id,
+		})
+	}
+	return updated
+}
+
+// LockUpdate is a lock file that is out of date with the enums it was
+// checked against. It should be written once the code generated for the enums
+// is in place, so that a failed write does not leave the lock ahead of it.
+type LockUpdate struct {
+	Path string
+	Lock *Lock
+}
+
+// Write writes the updated lock to its file.
+func (u LockUpdate) Write() error {
+	return u.Lock.WriteFile(u.Path)
+}
+
+// checkLocks verifies the enums against their lock files in dir and returns
+// the locks that need to be written. Unless update is set, generation fails if
+// a locked wire value would be removed or changed; new instances are always
+// added to the lock.
+func checkLocks(dir string, enums []enumInfo, update bool) ([]LockUpdate, error) {
+	var updates []LockUpdate
+	snapshot := newSnapshot(enums)
+	for _, t := range snapshot.Types {
+		path := LockFilename(dir, t.Name)
+
+		lock, err := ReadLock(path)
+		if errors.Is(err, os.ErrNotExist) {
+			lock = &Lock{Type: t.Name}
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !update {
+			if err = lock.Check(t); err != nil {
+				return nil, lockDiagnostics(path, err)
+			}
+		}
+
+		updated := lock.Update(t)
+		if reflect.DeepEqual(updated, lock) {
+			continue
+		}
+		updates = append(updates, LockUpdate{Path: path, Lock: updated})
+	}
+	return updates, nil
+}
+
+// lockDiagnostics converts the violations reported by Check into diagnostics
+// positioned at the lock file.
+func lockDiagnostics(path string, err error) error {
+	violations := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		violations = joined.Unwrap()
+	}
+
+	errs := make([]error, 0, len(violations))
+	for _, violation := range violations {
+		errs = append(errs, errorAt(
+			RuleLock,
+			token.Position{Filename: path},
+			"lock file is out of date: %v",
+			violation,
+		))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,231 @@
+package enumr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLockCheck(t *testing.T) {
+	lock := &Lock{
+		Type: "Method",
+		Entries: []LockEntry{
+			{Name: "CreditCard", Wire: "CC", ID: 0},
+			{Name: "PayPal", Wire: "PP", ID: 1},
+			{Name: "Cheque", Wire: "CH", ID: 2},
+		},
+		NextID: 3,
+	}
+
+	tests := []struct {
+		name      string
+		instances []InstanceSnapshot
+		wantErrs  []string
+	}{
+		{
+			name: "Unchanged",
+			instances: []InstanceSnapshot{
+				{Name: "CreditCard", Wire: "CC"},
+				{Name: "PayPal", Wire: "PP"},
+				{Name: "Cheque", Wire: "CH"},
+			},
+		},
+		{
+			name: "Added and renamed",
+			instances: []InstanceSnapshot{
+				{Name: "Card", Wire: "CC"},
+				{Name: "PayPal", Wire: "PP"},
+				{Name: "Cheque", Wire: "CH"},
+				{Name: "Bank", Wire: "BT"},
+			},
+		},
+		{
+			name: "Changed and removed",
+			instances: []InstanceSnapshot{
+				{Name: "CreditCard", Wire: "CARD"},
+				{Name: "PayPal", Wire: "PP"},
+			},
+			wantErrs: []string{
+				`Method.CreditCard: locked wire value "CC" changed to "CARD"`,
+				`Method.Cheque: locked wire value "CH" was removed`,
+			},
+		},
+		{
+			name: "Swapped",
+			instances: []InstanceSnapshot{
+				{Name: "CreditCard", Wire: "PP"},
+				{Name: "PayPal", Wire: "CC"},
+				{Name: "Cheque", Wire: "CH"},
+			},
+			wantErrs: []string{
+				`Method.CreditCard: locked wire value "CC" changed to "PP" and is now used by PayPal`,
+				`Method.PayPal: locked wire value "PP" changed to "CC" and is now used by CreditCard`,
+			},
+		},
+		{
+			name: "Renamed onto another wire value",
+			instances: []InstanceSnapshot{
+				{Name: "Card", Wire: "CC"},
+				{Name: "PayPal", Wire: "CH"},
+			},
+			wantErrs: []string{
+				`Method.PayPal: locked wire value "PP" changed to "CH"`,
+				`Method.Cheque: locked wire value "CH" is now used by PayPal`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := lock.Check(TypeSnapshot{Name: "Method", Instances: tt.instances})
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("Check() error = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Check() error = nil; want error")
+			}
+			if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, tt.wantErrs) {
+				t.Errorf("Check() errors = %q; want %q", got, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestLockUpdate(t *testing.T) {
+	lock := &Lock{
+		Type: "Method",
+		Entries: []LockEntry{
+			{Name: "CreditCard", Wire: "CC", ID: 0},
+			{Name: "PayPal", Wire: "PP", ID: 1},
+			{Name: "Cheque", Wire: "CH", ID: 2},
+		},
+		NextID: 3,
+	}
+
+	got := lock.Update(TypeSnapshot{
+		Name: "Method",
+		Instances: []InstanceSnapshot{
+			{Name: "Card", Wire: "CC"},
+			{Name: "PayPal", Wire: "PAYPAL"},
+			{Name: "Bank", Wire: "BT"},
+		},
+	})
+
+	want := &Lock{
+		Type: "Method",
+		Entries: []LockEntry{
+			{Name: "Card", Wire: "CC", ID: 0},
+			{Name: "PayPal", Wire: "PAYPAL", ID: 1},
+			{Name: "Bank", Wire: "BT", ID: 3},
+		},
+		NextID: 4,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Update() = %+v; want %+v", got, want)
+	}
+}
+
+func TestCheckLocks(t *testing.T) {
+	dir := t.TempDir()
+	enums := []enumInfo{
+		{
+			TypeName:   "MyEnum",
+			CaseFormat: "snake_case",
+			Instances:  []instanceData{{Name: "ValueOne"}, {Name: "ValueTwo"}},
+		},
+	}
+	path := filepath.Join(dir, "my_enum.enumr.lock")
+
+	updates, err := checkLocks(dir, enums, false)
+	if err != nil {
+		t.Fatalf("checkLocks() on new lock failed: %v", err)
+	}
+	if len(updates) != 1 || updates[0].Path != path {
+		t.Fatalf("checkLocks() = %+v; want an update of %s", updates, path)
+	}
+	if _, err = os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("checkLocks() wrote the lock file: %v", err)
+	}
+	if err = updates[0].Write(); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	if updates, err = checkLocks(dir, enums, false); err != nil || len(updates) != 0 {
+		t.Fatalf("checkLocks() on current lock = %+v, %v; want no updates", updates, err)
+	}
+
+	enums[0].Instances = enums[0].Instances[:1]
+	if _, err = checkLocks(dir, enums, false); err == nil {
+		t.Fatal("checkLocks() with removed instance succeeded; want error")
+	}
+
+	updates, err = checkLocks(dir, enums, true)
+	if err != nil || len(updates) != 1 {
+		t.Fatalf("checkLocks() with update = %+v, %v; want one update", updates, err)
+	}
+	if err = updates[0].Write(); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	lock, err := ReadLock(path)
+	if err != nil {
+		t.Fatalf("ReadLock failed: %v", err)
+	}
+	if len(lock.Entries) != 1 || lock.NextID != 2 {
+		t.Errorf("updated lock = %+v; want one entry and NextID 2", lock)
+	}
+
+	if _, err = ReadLock(filepath.Join(dir, "missing.enumr.lock")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadLock() of missing file error = %v; want os.ErrNotExist", err)
+	}
+}
+
+func TestLockIDs(t *testing.T) {
+	id := func(id int) *int { return &id }
+	lock := &Lock{
+		Type: "Method",
+		Entries: []LockEntry{
+			{Name: "CreditCard", Wire: "CC", ID: 1},
+			{Name: "Cheque", Wire: "CH", ID: 2},
+		},
+		NextID: 3,
+	}
+
+	err := lock.Check(TypeSnapshot{Name: "Method", Instances: []InstanceSnapshot{
+		{Name: "CreditCard", Wire: "CC", ID: id(5)},
+		{Name: "Bank", Wire: "BT", ID: id(2)},
+	}})
+	want := []string{
+		`Method.Cheque: locked wire value "CH" was removed`,
+		`Method.CreditCard: locked ID 1 changed to 5`,
+		`Method.Bank: ID 2 is locked for Cheque`,
+	}
+	if err == nil {
+		t.Fatal("Check() error = nil; want error")
+	}
+	if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() errors = %q; want %q", got, want)
+	}
+
+	got := lock.Update(TypeSnapshot{Name: "Method", Instances: []InstanceSnapshot{
+		{Name: "CreditCard", Wire: "CC", ID: id(1)},
+		{Name: "Bank", Wire: "BT", ID: id(7)},
+	}})
+	wantLock := &Lock{
+		Type: "Method",
+		Entries: []LockEntry{
+			{Name: "CreditCard", Wire: "CC", ID: 1},
+			{Name: "Bank", Wire: "BT", ID: 7},
+		},
+		NextID: 8,
+	}
+	if !reflect.DeepEqual(got, wantLock) {
+		t.Errorf("Update() = %+v; want %+v", got, wantLock)
+	}
+}
@@ -0,0 +1,54 @@
+package enumr
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+)
+
+// resolveOrder sorts the instances by their @order attributes. Without any
+// @order attribute, instances with IDs are sorted by ID and others keep their
+// declaration order; otherwise every instance needs a distinct integer @order.
+func (e *enumInfo) resolveOrder() error {
+	hasOrder := func(instance instanceData) bool {
+		_, ok := instance.Attributes["order"]
+		return ok
+	}
+	if !slices.ContainsFunc(e.Instances, hasOrder) {
+		if e.IDs {
+			slices.SortStableFunc(e.Instances, func(a, b instanceData) int {
+				return cmp.Compare(a.ID, b.ID)
+			})
+		}
+		return nil
+	}
+
+	keys := make(map[string]int, len(e.Instances))
+	owners := make(map[int]string, len(e.Instances))
+	for _, instance := range e.Instances {
+		attr, ok := instance.Attributes["order"]
+		if !ok {
+			return errorAt(
+				RuleInvalidAttribute,
+				instance.Pos,
+				"instance %s has no @order, but other instances of %s do",
+				instance.Name,
+				e.TypeName,
+			)
+		}
+		key, err := strconv.Atoi(attr.Value)
+		if err != nil {
+			return errorAt(RuleInvalidAttribute, attr.Pos, "@order of %s is not an integer: %q", instance.Name, attr.Value)
+		}
+		if owner, taken := owners[key]; taken {
+			return errorAt(RuleInvalidAttribute, attr.Pos, "@order %d of %s is already used by %s", key, instance.Name, owner)
+		}
+		owners[key] = instance.Name
+		keys[instance.Name] = key
+	}
+
+	slices.SortStableFunc(e.Instances, func(a, b instanceData) int {
+		return cmp.Compare(keys[a.Name], keys[b.Name])
+	})
+	return nil
+}
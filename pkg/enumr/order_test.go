@@ -0,0 +1,92 @@
+package enumr
+
+import (
+	"go/token"
+	"slices"
+	"testing"
+)
+
+func TestResolveOrder(t *testing.T) {
+	pos := token.Position{Filename: "level.go", Line: 5, Column: 14}
+	order := func(value string) attributes {
+		return attributes{"order": {Value: value, Pos: pos}}
+	}
+	tests := []struct {
+		name      string
+		instances []instanceData
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name:      "Declaration order",
+			instances: []instanceData{{Name: "Low"}, {Name: "High"}},
+			expected:  []string{"Low", "High"},
+		},
+		{
+			name: "Explicit order",
+			instances: []instanceData{
+				{Name: "High", Attributes: order("30")},
+				{Name: "Low", Attributes: order("10")},
+				{Name: "Medium", Attributes: order("20")},
+			},
+			expected: []string{"Low", "Medium", "High"},
+		},
+		{
+			name:      "Missing order",
+			instances: []instanceData{{Name: "High", Attributes: order("2")}, {Name: "Low", Pos: pos}},
+			wantErr:   true,
+		},
+		{
+			name:      "Not an integer",
+			instances: []instanceData{{Name: "High", Attributes: order("last")}},
+			wantErr:   true,
+		},
+		{
+			name:      "Duplicate",
+			instances: []instanceData{{Name: "High", Attributes: order("1")}, {Name: "Low", Attributes: order("1")}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enum := enumInfo{TypeName: "Level", Instances: tt.instances}
+			err := enum.resolveOrder()
+			if tt.wantErr {
+				diags := Diagnostics(err)
+				if len(diags) != 1 || diags[0].Rule != RuleInvalidAttribute || diags[0].Pos != pos {
+					t.Errorf("resolveOrder() error = %v; want %s at %v", err, RuleInvalidAttribute, pos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveOrder() failed: %v", err)
+			}
+			var names []string
+			for _, instance := range enum.Instances {
+				names = append(names, instance.Name)
+			}
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("resolveOrder() order = %v; want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveOrderByID(t *testing.T) {
+	enum := enumInfo{
+		TypeName:  "Level",
+		IDs:       true,
+		Instances: []instanceData{{Name: "High", ID: 3}, {Name: "Low", ID: 1}, {Name: "Medium", ID: 2}},
+	}
+	if err := enum.resolveOrder(); err != nil {
+		t.Fatalf("resolveOrder() failed: %v", err)
+	}
+	var names []string
+	for _, instance := range enum.Instances {
+		names = append(names, instance.Name)
+	}
+	if want := []string{"Low", "Medium", "High"}; !slices.Equal(names, want) {
+		t.Errorf("resolveOrder() order = %v; want %v", names, want)
+	}
+}
@@ -0,0 +1,108 @@
+package enumr
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// OutputFile is a file that a group of enums is generated into.
+type OutputFile struct {
+	Path  string
+	Types []string
+}
+
+// OutputFiles returns the files the given types of a package are generated
+// into. With opts.Split each type gets its own file named as by
+// GetOutputFilename. Otherwise all types share one file, named after the type
+// if there is only one and after the package if there are several, so that
+// the name does not depend on the order of typeNames.
+func OutputFiles(dir, packageName string, typeNames []string, output string, opts Options) []OutputFile {
+	if len(typeNames) == 0 {
+		return nil
+	}
+
+	if opts.Split {
+		files := make([]OutputFile, 0, len(typeNames))
+		for _, typeName := range typeNames {
+			files = append(files, OutputFile{
+				Path:  GetOutputFilename(dir, typeName, output),
+				Types: []string{typeName},
+			})
+		}
+		return files
+	}
+
+	name := packageName
+	if len(typeNames) == 1 {
+		name = typeNames[0]
+	}
+	return []OutputFile{{Path: GetOutputFilename(dir, name, output), Types: typeNames}}
+}
+
+// StaleOutputs returns the files in dir that enumr generated for the package
+// and that only contain some of the given types, excluding the files in keep.
+// These are left over from a previous run that grouped the types differently
+// and can be removed once the files in keep are written.
+func StaleOutputs(dir, packageName string, typeNames, keep []string) ([]string, error) {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, file := range files {
+		path := fset.Position(file.Package).Filename
+		if file.Name.Name != packageName || !IsGeneratedFile(file) {
+			continue
+		}
+		if slices.ContainsFunc(keep, func(k string) bool { return sameFile(k, path) }) {
+			continue
+		}
+		generated := generatedTypes(file)
+		if len(generated) == 0 {
+			continue
+		}
+		if !slices.ContainsFunc(generated, func(typeName string) bool {
+			return !slices.Contains(typeNames, typeName)
+		}) {
+			stale = append(stale, path)
+		}
+	}
+	return stale, nil
+}
+
+// generatedTypes returns the types that a generated file provides Parse
+// functions for.
+func generatedTypes(file *ast.File) []string {
+	var typeNames []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+		if typeName, ok := strings.CutPrefix(fn.Name.Name, "Parse"); ok && typeName != "" {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	return typeNames
+}
+
+// sameFile reports whether the paths name the same file.
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	if absA == absB {
+		return true
+	}
+	infoA, errA := os.Stat(absA)
+	infoB, errB := os.Stat(absB)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,77 @@
+package enumr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOutputFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		typeNames []string
+		opts      Options
+		expected  []OutputFile
+	}{
+		{
+			name:      "Single type",
+			typeNames: []string{"Method"},
+			expected:  []OutputFile{{Path: filepath.Join("/tmp", "method_enum.go"), Types: []string{"Method"}}},
+		},
+		{
+			name:      "Combined types",
+			typeNames: []string{"Status", "Method"},
+			expected: []OutputFile{
+				{Path: filepath.Join("/tmp", "payment_enum.go"), Types: []string{"Status", "Method"}},
+			},
+		},
+		{
+			name:      "Split types",
+			typeNames: []string{"Status", "Method"},
+			opts:      Options{Split: true},
+			expected: []OutputFile{
+				{Path: filepath.Join("/tmp", "status_enum.go"), Types: []string{"Status"}},
+				{Path: filepath.Join("/tmp", "method_enum.go"), Types: []string{"Method"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OutputFiles("/tmp", "payment", tt.typeNames, "", tt.opts)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("OutputFiles() = %v; want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStaleOutputs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"payment.go":     "package payment\n\ntype Method struct{}\n\ntype Status struct{}\n\ntype Other struct{}\n",
+		"method_enum.go": generatedHeader + "\n\npackage payment\n\nfunc ParseMethod(s string) (Method, error) { return Method{}, nil }\n",
+		"status_enum.go": generatedHeader + "\n\npackage payment\n\nfunc ParseStatus(s string) (Status, error) { return Status{}, nil }\n",
+		"other_enum.go":  generatedHeader + "\n\npackage payment\n\nfunc ParseOther(s string) (Other, error) { return Other{}, nil }\n",
+		"mixed_enum.go": generatedHeader + "\n\npackage payment\n\n" +
+			"func ParseMethod2(s string) (Method, error) { return Method{}, nil }\n" +
+			"func ParseOther2(s string) (Other, error) { return Other{}, nil }\n",
+		"payment_enum.go": generatedHeader + "\n\npackage payment\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keep := []string{filepath.Join(dir, "payment_enum.go"), filepath.Join(dir, "status_enum.go")}
+	got, err := StaleOutputs(dir, "payment", []string{"Method", "Status", "Method2"}, keep)
+	if err != nil {
+		t.Fatalf("StaleOutputs failed: %v", err)
+	}
+	want := []string{filepath.Join(dir, "method_enum.go")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StaleOutputs() = %v; want %v", got, want)
+	}
+}
@@ -2,10 +2,10 @@ package enumr
 
 import (
 	"bytes"
-	"fmt"
 	"go/ast"
 	"go/printer"
 	"go/token"
+	"go/types"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -17,7 +17,7 @@ func processTypeSpec(pkg *packages.Package, typeName string) (*typeSpec, error)
 		return nil, err
 	}
 
-	fields := extractFields(pkg, decl.spec)
+	fields := extractFields(pkg.TypesInfo, decl.spec)
 
 	doc := decl.genDecl.Doc
 	if decl.spec.Doc != nil {
@@ -56,11 +56,11 @@ func findTypeDeclaration(pkg *packages.Package, typeName string) (*typeDeclarati
 			}
 		}
 	}
-	return nil, fmt.Errorf("type %s not found in package", typeName)
+	return nil, errorAt(RuleTypeNotFound, token.Position{}, "type %s not found in package", typeName)
 }
 
 // extractFields extracts field information from a struct type specification.
-func extractFields(pkg *packages.Package, typeSpec *ast.TypeSpec) []fieldInfo {
+func extractFields(info *types.Info, typeSpec *ast.TypeSpec) []fieldInfo {
 	var fields []fieldInfo
 	structType, ok := typeSpec.Type.(*ast.StructType)
 	if !ok {
@@ -68,7 +68,7 @@ func extractFields(pkg *packages.Package, typeSpec *ast.TypeSpec) []fieldInfo {
 	}
 
 	for _, field := range structType.Fields.List {
-		typeStr := resolveFieldType(pkg, field)
+		typeStr := resolveFieldType(info, field)
 		for _, name := range field.Names {
 			fields = append(fields, fieldInfo{Name: name.Name, Type: typeStr})
 		}
@@ -77,9 +77,9 @@ func extractFields(pkg *packages.Package, typeSpec *ast.TypeSpec) []fieldInfo {
 }
 
 // resolveFieldType resolves the type string for a given field.
-func resolveFieldType(pkg *packages.Package, field *ast.Field) string {
-	if pkg.TypesInfo != nil {
-		if tv, ok := pkg.TypesInfo.Types[field.Type]; ok {
+func resolveFieldType(info *types.Info, field *ast.Field) string {
+	if info != nil {
+		if tv, ok := info.Types[field.Type]; ok {
 			return tv.Type.String()
 		}
 	}
@@ -107,8 +107,14 @@ func collectInstances(pkg *packages.Package, typeName string, fields []fieldInfo
 					continue
 				}
 
+				// Attributes may be set in the doc of a single var declaration
+				doc := valueSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+
 				// Collect instances related to the type
-				collectVarsOfType(pkg, valueSpec, typeName, fields, &instances)
+				collectVarsOfType(pkg, valueSpec, doc, typeName, fields, &instances)
 			}
 		}
 	}
@@ -119,6 +125,7 @@ func collectInstances(pkg *packages.Package, typeName string, fields []fieldInfo
 func collectVarsOfType(
 	pkg *packages.Package,
 	valueSpec *ast.ValueSpec,
+	doc *ast.CommentGroup,
 	typeName string,
 	fields []fieldInfo,
 	instances *[]instanceData,
@@ -140,6 +147,8 @@ func collectVarsOfType(
 			*instances = append(*instances, instanceData{
 				Name:   valueSpec.Names[i].Name,
 				Fields: extractFieldValues(pkg, v, fields),
+				Doc:    doc,
+				Pos:    pkg.Fset.Position(valueSpec.Names[i].Pos()),
 			})
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
+	"slices"
 	"strings"
 	"text/template"
 )
@@ -16,12 +17,25 @@ func generateEnumSource(
 	packageName string,
 	enums []enumInfo,
 ) ([]byte, error) {
+	return renderSource(enumData{
+		PackageName: packageName,
+		Enums:       enums,
+	})
+}
+
+// renderSource executes the template for the given data.
+func renderSource(data enumData) ([]byte, error) {
+	data.Imports = sourceImports(data.Enums)
+
 	// Create the template object with a function map for name transformations
 	tmplFuncs := template.FuncMap{
 		"transformName": func(name, format string) string {
 			return transformName(format)(name)
 		},
 		"renderInit": renderInit,
+		"last": func(instances []instanceData) instanceData {
+			return instances[len(instances)-1]
+		},
 	}
 
 	tmpl, err := template.New("enumTemplate").Funcs(tmplFuncs).Parse(enumTemplate)
@@ -29,12 +43,6 @@ func generateEnumSource(
 		return nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	// Define the data to pass to the template
-	data := enumData{
-		PackageName: packageName,
-		Enums:       enums,
-	}
-
 	// Apply the template to the data and write it to the buffer
 	var buf bytes.Buffer
 	err = tmpl.Execute(&buf, data)
@@ -45,6 +53,90 @@ func generateEnumSource(
 	return buf.Bytes(), nil
 }
 
+// runtimeImport imports the package providing runtime support for generated
+// code under its package name, which differs from the last element of its path.
+var runtimeImport = importSpec{Name: "enumr", Path: modulePath}
+
+// sourceImports returns the imports needed by the code generated for the
+// enums, sorted by path.
+func sourceImports(enums []enumInfo) []importSpec {
+	// Errors are built with fmt, and Validate formats the fields of invalid
+	// values with it
+	imports := []importSpec{{Path: "fmt"}}
+	for _, enum := range enums {
+		if enum.Runtime {
+			imports = append(imports, runtimeImport)
+		}
+		if enum.ParseMode.Fold || enum.ParseMode.Trim {
+			imports = append(imports, importSpec{Path: "strings"})
+		}
+		if enum.ParseMode.Normalize != "" {
+			imports = append(imports, importSpec{Path: "golang.org/x/text/unicode/norm"})
+		}
+		if enum.Ordinal {
+			imports = append(imports, importSpec{Path: "cmp"})
+		}
+		if enum.Set {
+			imports = append(
+				imports,
+				importSpec{Path: "encoding/json"},
+				importSpec{Path: "iter"},
+				importSpec{Path: "math/bits"},
+				importSpec{Path: "strings"},
+			)
+		}
+		if enum.Map {
+			imports = append(
+				imports,
+				importSpec{Path: "encoding/json"},
+				importSpec{Path: "iter"},
+				importSpec{Path: "maps"},
+				importSpec{Path: "math/bits"},
+				importSpec{Path: "slices"},
+			)
+		}
+		if enum.Match {
+			imports = append(imports, importSpec{Path: "fmt"}, importSpec{Path: "strings"})
+		}
+		if enum.IDs {
+			imports = append(imports, importSpec{Path: "encoding/binary"}, importSpec{Path: "fmt"})
+		}
+		if enum.SQL {
+			imports = append(imports, importSpec{Path: "database/sql/driver"}, importSpec{Path: "fmt"})
+		}
+	}
+	slices.SortFunc(imports, func(a, b importSpec) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return slices.Compact(imports)
+}
+
+// formatHeader turns a custom file header into a comment block without
+// trailing blank lines. A header that is already a comment is kept as is.
+func formatHeader(header string) string {
+	header = strings.TrimRight(header, " \t\r\n")
+	if header == "" {
+		return ""
+	}
+	if trimmed := strings.TrimLeft(header, " \t\r\n"); strings.HasPrefix(trimmed, "/*") {
+		return header
+	}
+
+	lines := strings.Split(header, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case strings.HasPrefix(line, "//"):
+		case line == "":
+			line = "//"
+		default:
+			line = "// " + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
 func renderInit(instance instanceData, fields []fieldInfo) string {
 	var parts []string
 	for _, field := range fields {
@@ -3,6 +3,8 @@ package enumr
 import (
 	"bytes"
 	"flag"
+	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
 	"strings"
@@ -117,3 +119,248 @@ func TestGenerateEnumSourceWithZero(t *testing.T) {
 		t.Errorf("generated source does not contain zero value case.\nGot:\n%s", source)
 	}
 }
+
+func TestFormatHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "Empty", header: "\n\n", expected: ""},
+		{name: "Plain text", header: "Copyright 2026 Example\n\nMIT License\n", expected: "// Copyright 2026 Example\n//\n// MIT License"},
+		{name: "Line comments", header: "// Copyright 2026 Example\n", expected: "// Copyright 2026 Example"},
+		{name: "Block comment", header: "/*\nCopyright 2026 Example\n*/\n", expected: "/*\nCopyright 2026 Example\n*/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatHeader(tt.header); got != tt.expected {
+				t.Errorf("formatHeader(%q) = %q; want %q", tt.header, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderSourceWithHeader(t *testing.T) {
+	source, err := renderSource(enumData{
+		Header:      formatHeader("Copyright 2026 Example"),
+		PackageName: "testpkg",
+		Enums:       []enumInfo{{TypeName: "MyEnum", Instances: []instanceData{{Name: "ValueOne"}}}},
+	})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+
+	want := "// Copyright 2026 Example\n\n" + generatedHeader + "\n\npackage testpkg\n"
+	if !strings.HasPrefix(string(source), want) {
+		t.Errorf("generated source does not start with header.\nExpected prefix:\n%s\nGot:\n%s", want, source)
+	}
+}
+
+func TestRenderSourceDeprecated(t *testing.T) {
+	enum := enumInfo{
+		TypeName:          "Method",
+		GenerateVars:      true,
+		ExcludeDeprecated: true,
+		Instances: []instanceData{
+			{Name: "BankTransfer"},
+			{Name: "Cheque", Deprecated: "use BankTransfer"},
+		},
+	}
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"\t// Deprecated: use BankTransfer\n\tCheque = Method{  }\n",
+		"var MethodDeprecatedHook func(value Method, text string)\n",
+		"\tcase \"Cheque\":\n\t\tif MethodDeprecatedHook != nil {\n\t\t\tMethodDeprecatedHook(Cheque, text)\n\t\t}\n",
+		"\treturn []Method{\n\t\tBankTransfer,\n\t}\n",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+}
+
+func TestRenderSourceDefault(t *testing.T) {
+	enum := enumInfo{
+		TypeName:    "Method",
+		IncludeZero: true,
+		Default:     "Unknown",
+		SQL:         true,
+		Instances:   []instanceData{{Name: "Unknown"}, {Name: "Card"}},
+	}
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"\tcase Unknown, Method{}:\n\t\treturn \"Unknown\"\n",
+		"\tcase \"\":\n\t\treturn Unknown, nil\n",
+		"\treturn t == Method{} || t == Unknown\n",
+		"\tcase Unknown, Card, Method{}:\n\t\treturn true\n",
+		"\tcase nil:\n\t\t*t = Unknown\n",
+		"\"database/sql/driver\"",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+	if strings.Contains(string(source), "return nil, nil") {
+		t.Errorf("Value stores the zero value as NULL despite a default instance:\n%s", source)
+	}
+}
+
+func TestRenderSourceUnknownField(t *testing.T) {
+	enum := enumInfo{
+		TypeName:     "Method",
+		UnknownField: "raw",
+		Instances:    []instanceData{{Name: "Card"}, {Name: "Cash"}},
+	}
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"\t}\n\treturn t.raw\n}\n",
+		"\tif err != nil {\n\t\tval = Method{raw: string(text)}\n\t}\n",
+		"func (t Method) IsKnown() bool {\n\treturn t.IsValid()\n}\n",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+}
+
+func TestRenderSourceIDs(t *testing.T) {
+	enum := enumInfo{
+		TypeName:  "Method",
+		IDs:       true,
+		Instances: []instanceData{{Name: "Cash", ID: 2}, {Name: "Card", ID: 7}},
+	}
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"\tcase Card:\n\t\treturn 7\n",
+		"\tcase 7:\n\t\treturn Card, true\n",
+		"\treturn binary.AppendUvarint(nil, uint64(id)), nil\n",
+		"\"encoding/binary\"",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+}
+
+func TestRenderSourceLargeSet(t *testing.T) {
+	enum := enumInfo{TypeName: "Code", Ordinal: true, Set: true}
+	for i := range 65 {
+		enum.Instances = append(enum.Instances, instanceData{Name: fmt.Sprintf("Code%d", i)})
+	}
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+	if want := "\twords [2]uint64\n"; !strings.Contains(string(source), want) {
+		t.Errorf("generated source does not contain %q:\n%s", want, source)
+	}
+	if _, err = format.Source(source); err != nil {
+		t.Errorf("generated source is not valid Go: %v", err)
+	}
+}
+
+func TestRenderSourceMap(t *testing.T) {
+	enum := enumInfo{
+		TypeName:  "Method",
+		Ordinal:   true,
+		Map:       true,
+		Instances: []instanceData{{Name: "Card"}, {Name: "Cash"}},
+	}
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"type MethodMap[V any] struct {\n\tvalues  [MethodCount]V\n\tpresent [1]uint64\n}\n",
+		"func (m *MethodMap[V]) All() iter.Seq2[Method, V] {\n",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+}
+
+func TestRenderSourceMatch(t *testing.T) {
+	enum := enumInfo{
+		TypeName:  "Method",
+		Match:     true,
+		Default:   "Unknown",
+		Instances: []instanceData{{Name: "Unknown"}, {Name: "Card"}},
+	}
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"type MethodCases[R any] struct {\n\tUnknown func() R\n\tCard func() R\n}\n",
+		"\tcase Unknown, Method{}:\n\t\thandler = cases.Unknown\n",
+		"\tif c.Card == nil {\n\t\tmissing = append(missing, \"Card\")\n\t}\n",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+}
+
+func TestRenderSourceRuntime(t *testing.T) {
+	enum := enumInfo{
+		TypeName:  "Method",
+		IDs:       true,
+		Match:     true,
+		Instances: []instanceData{{Name: "Cash", ID: 2}, {Name: "Card", ID: 7}},
+	}
+
+	// By default the generated code only depends on the standard library
+	source, err := renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+	if strings.Contains(string(source), modulePath) || strings.Contains(string(source), "enumr.Err") ||
+		strings.Contains(string(source), "enumr.New") {
+		t.Errorf("generated source refers to the runtime package:\n%s", source)
+	}
+	for _, want := range []string{
+		"return Method{}, fmt.Errorf(\"unknown Method value %q\", text)\n",
+		"return fmt.Errorf(\"unknown Method ID %d\", id)\n",
+		"return zero, fmt.Errorf(\"no handler for Method value %q\", t)\n",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+
+	enum.Runtime = true
+	source, err = renderSource(enumData{PackageName: "testpkg", Enums: []enumInfo{enum}})
+	if err != nil {
+		t.Fatalf("renderSource failed: %v", err)
+	}
+	for _, want := range []string{
+		"enumr \"github.com/jmfrees/go-enumr\"",
+		"return Method{}, enumr.NewUnknownValueError(\"Method\", text, []string{\"Cash\", \"Card\"})\n",
+		"return fmt.Errorf(\"%w: Method ID %d\", enumr.ErrUnknownValue, id)\n",
+		"return zero, fmt.Errorf(\"%w for Method value %q\", enumr.ErrNoHandler, t)\n",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package enumr
+
+import (
+	"errors"
+	"strings"
+)
+
+// checkSetValues reports instances whose string representation contains a
+// comma, which <Type>Set uses to separate values in its text encoding.
+func (e enumInfo) checkSetValues() error {
+	if !e.Set {
+		return nil
+	}
+	var errs []error
+	for _, instance := range e.Instances {
+		if value := e.wireValue(instance); strings.Contains(value, ",") {
+			errs = append(errs, errorAt(
+				RuleSetSeparator,
+				instance.Pos,
+				"%s marshals to %q, which contains the comma that separates the values of %sSet",
+				instance.Name,
+				value,
+				e.TypeName,
+			))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,49 @@
+package enumr
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestCheckSetValues(t *testing.T) {
+	pos := token.Position{Filename: "size.go", Line: 4, Column: 1}
+	tests := []struct {
+		name      string
+		set       bool
+		instances []instanceData
+		wantErr   bool
+	}{
+		{
+			name:      "Plain values",
+			set:       true,
+			instances: []instanceData{{Name: "Small", Fields: map[string]string{"Label": `"S"`}, Pos: pos}},
+		},
+		{
+			name:      "Comma",
+			set:       true,
+			instances: []instanceData{{Name: "Small", Fields: map[string]string{"Label": `"S,M"`}, Pos: pos}},
+			wantErr:   true,
+		},
+		{
+			name:      "Comma without set",
+			instances: []instanceData{{Name: "Small", Fields: map[string]string{"Label": `"S,M"`}, Pos: pos}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enum := enumInfo{TypeName: "Size", Instances: tt.instances, Set: tt.set, MarshalField: "Label"}
+			err := enum.checkSetValues()
+			if tt.wantErr {
+				diags := Diagnostics(err)
+				if len(diags) != 1 || diags[0].Rule != RuleSetSeparator || diags[0].Pos != pos {
+					t.Errorf("checkSetValues() error = %v; want %s at %v", err, RuleSetSeparator, pos)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("checkSetValues() error = %v", err)
+			}
+		})
+	}
+}
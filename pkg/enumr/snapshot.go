@@ -0,0 +1,119 @@
+package enumr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Snapshot is a serializable view of the resolved enum model. It records
+// what each instance marshals to so that enum definitions can be compared
+// across versions.
+type Snapshot struct {
+	Types []TypeSnapshot `json:"types"`
+}
+
+// TypeSnapshot holds the resolved instances of a single enum type.
+type TypeSnapshot struct {
+	Name      string             `json:"name"`
+	Instances []InstanceSnapshot `json:"instances"`
+}
+
+// InstanceSnapshot holds the wire representation and field values of an instance.
+type InstanceSnapshot struct {
+	Name   string            `json:"name"`
+	Wire   string            `json:"wire"`
+	Fields map[string]string `json:"fields,omitempty"`
+	// Aliases are the additional strings that parse to the instance.
+	Aliases []string `json:"aliases,omitempty"`
+	// ID is the explicit ID of the instance, if the type has IDs.
+	ID *int `json:"id,omitempty"`
+}
+
+// Snapshot resolves the given types in the package and returns their model
+// without generating any code.
+func (g *Generator) Snapshot(
+	ctx context.Context,
+	pkg *packages.Package,
+	typeNames []string,
+	opts Options,
+) (*Snapshot, error) {
+	enums, err := g.resolveEnums(ctx, pkg, typeNames, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newSnapshot(enums), nil
+}
+
+// ReadSnapshot decodes a snapshot previously written with WriteTo.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &s, nil
+}
+
+// WriteTo encodes the snapshot as indented JSON.
+func (s *Snapshot) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Type returns the snapshot of the named type, if present.
+func (s *Snapshot) Type(name string) (TypeSnapshot, bool) {
+	for _, t := range s.Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TypeSnapshot{}, false
+}
+
+func newSnapshot(enums []enumInfo) *Snapshot {
+	s := &Snapshot{Types: make([]TypeSnapshot, 0, len(enums))}
+	for _, enum := range enums {
+		t := TypeSnapshot{
+			Name:      enum.TypeName,
+			Instances: make([]InstanceSnapshot, 0, len(enum.Instances)),
+		}
+		for _, instance := range enum.Instances {
+			snapshot := InstanceSnapshot{
+				Name:    instance.Name,
+				Wire:    enum.wireValue(instance),
+				Fields:  maps.Clone(instance.Fields),
+				Aliases: slices.Clone(instance.Aliases),
+			}
+			if enum.IDs {
+				snapshot.ID = &instance.ID
+			}
+			t.Instances = append(t.Instances, snapshot)
+		}
+		s.Types = append(s.Types, t)
+	}
+	return s
+}
+
+// wireValue returns the string an instance marshals to. Values of the marshal
+// field that are not string literals (e.g. constant references) are returned
+// as their Go expression.
+func (e enumInfo) wireValue(instance instanceData) string {
+	if e.MarshalField == "" {
+		return transformName(e.CaseFormat)(instance.Name)
+	}
+	expr := instance.Fields[e.MarshalField]
+	if s, err := strconv.Unquote(expr); err == nil {
+		return s
+	}
+	return expr
+}
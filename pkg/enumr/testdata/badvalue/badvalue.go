@@ -0,0 +1,7 @@
+package badvalue
+
+// enumr:Small Size:1
+// enumr:Large Size:large
+type Size struct {
+	Size int
+}
@@ -0,0 +1,8 @@
+package normalize
+
+// enumr:@parse=nfc
+// enumr:Zurich   Code:ZRH @alias=zürich
+// enumr:SaoPaulo Code:GRU @alias=são_paulo
+type City struct {
+	Code string
+}
@@ -0,0 +1,17 @@
+package payment
+
+// enumr:CreditCard Code:CC Desc:"Credit Card"
+// enumr:PayPal     Code:PP Desc:PayPal
+type Method struct {
+	Code string
+	Desc string
+}
+
+type Status struct {
+	ID int
+}
+
+var (
+	Active   = Status{ID: 1}
+	Inactive = Status{ID: 2}
+)
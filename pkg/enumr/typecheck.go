@@ -0,0 +1,238 @@
+package enumr
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typeCheck type-checks the package with source in place of any file
+// previously generated for the same types. Only errors in the generated
+// source are reported; they are attributed to the directive or var that
+// declared the offending instance where possible.
+func typeCheck(pkg *packages.Package, enums []enumInfo, source []byte) error {
+	filename := filepath.Join(pkg.Dir, "enumr_candidate.go")
+	candidate, err := parser.ParseFile(pkg.Fset, filename, source, parser.ParseComments)
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) && len(list) > 0 && candidate != nil {
+			file := pkg.Fset.File(candidate.Package)
+			return typeCheckError(pkg.Fset, enums, candidate, file.Pos(list[0].Pos.Offset), list[0].Msg)
+		}
+		return errorAt(RuleTypeCheck, token.Position{}, "generated code does not parse: %v", err)
+	}
+
+	typeNames := make([]string, 0, len(enums))
+	for _, enum := range enums {
+		typeNames = append(typeNames, enum.TypeName)
+	}
+
+	files := []*ast.File{candidate}
+	for _, file := range pkg.Syntax {
+		if !replacedByCandidate(file, typeNames) {
+			files = append(files, file)
+		}
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	conf := types.Config{
+		Importer: newPackageImporter(pkg),
+		Sizes:    pkg.TypesSizes,
+		Error: func(err error) {
+			typeErr, ok := err.(types.Error)
+			if !ok {
+				return
+			}
+			if pkg.Fset.Position(typeErr.Pos).Filename != filename || seen[typeErr.Msg] {
+				return
+			}
+			seen[typeErr.Msg] = true
+			if diag, ok := importError(candidate, typeErr.Pos); ok {
+				errs = append(errs, diag)
+				return
+			}
+			errs = append(errs, typeCheckError(pkg.Fset, enums, candidate, typeErr.Pos, typeErr.Msg))
+		},
+	}
+	_, _ = conf.Check(pkg.PkgPath, pkg.Fset, files, nil)
+
+	return errors.Join(errs...)
+}
+
+// requiredModules maps the packages outside the standard library that
+// generated code may import to the modules providing them.
+var requiredModules = map[string]string{
+	modulePath:                       modulePath,
+	"golang.org/x/text/unicode/norm": "golang.org/x/text",
+}
+
+// importError returns a diagnostic explaining how to add the module of a
+// package imported by the generated code, if pos is in an import of such a
+// package that failed.
+func importError(candidate *ast.File, pos token.Pos) (*Diagnostic, bool) {
+	for _, spec := range candidate.Imports {
+		if pos < spec.Pos() || pos >= spec.End() {
+			continue
+		}
+		path, _ := strconv.Unquote(spec.Path.Value)
+		module, ok := requiredModules[path]
+		if !ok {
+			return nil, false
+		}
+		return errorAt(
+			RuleTypeCheck,
+			token.Position{},
+			"generated code imports %s, which the module cannot resolve; add it with \"go get %s\"",
+			path,
+			module,
+		), true
+	}
+	return nil, false
+}
+
+// typeCheckError returns a diagnostic for an error at pos in the generated
+// file, positioned at the declaration of the instance whose generated code
+// contains pos.
+func typeCheckError(fset *token.FileSet, enums []enumInfo, candidate *ast.File, pos token.Pos, msg string) error {
+	if instance, ok := enclosingInstance(enums, candidate, pos); ok && instance.Pos.IsValid() {
+		return errorAt(
+			RuleTypeCheck,
+			instance.Pos,
+			"generated code for %s does not type-check: %s",
+			instance.Name,
+			msg,
+		)
+	}
+	at := fset.Position(pos)
+	return errorAt(
+		RuleTypeCheck,
+		token.Position{},
+		"generated code does not type-check: %d:%d: %s",
+		at.Line,
+		at.Column,
+		msg,
+	)
+}
+
+// enclosingInstance finds the instance whose var declaration or switch case
+// in the generated file contains pos.
+func enclosingInstance(enums []enumInfo, candidate *ast.File, pos token.Pos) (instanceData, bool) {
+	if candidate == nil || !pos.IsValid() {
+		return instanceData{}, false
+	}
+
+	var name string
+	ast.Inspect(candidate, func(n ast.Node) bool {
+		if n == nil || pos < n.Pos() || pos >= n.End() {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			if len(n.Names) > 0 {
+				name = n.Names[0].Name
+			}
+		case *ast.CaseClause:
+			if len(n.List) > 0 {
+				if ident, ok := n.List[0].(*ast.Ident); ok {
+					name = ident.Name
+				}
+			}
+		}
+		return true
+	})
+
+	for _, enum := range enums {
+		for _, instance := range enum.Instances {
+			if instance.Name == name {
+				return instance, true
+			}
+		}
+	}
+	return instanceData{}, false
+}
+
+// replacedByCandidate reports whether the file was generated by enumr for
+// one of the given types, and is therefore replaced by the new output.
+func replacedByCandidate(file *ast.File, typeNames []string) bool {
+	if !IsGeneratedFile(file) {
+		return false
+	}
+	return slices.ContainsFunc(generatedTypes(file), func(typeName string) bool {
+		return slices.Contains(typeNames, typeName)
+	})
+}
+
+// packageImporter resolves imports from the packages already loaded as
+// dependencies, falling back to export data located with the go command for
+// packages the generated code imports but the package itself does not.
+type packageImporter struct {
+	loaded   map[string]*types.Package
+	fallback types.Importer
+}
+
+func newPackageImporter(pkg *packages.Package) *packageImporter {
+	loaded := make(map[string]*types.Package)
+	packages.Visit([]*packages.Package{pkg}, nil, func(p *packages.Package) {
+		if p != pkg && p.Types != nil {
+			loaded[p.PkgPath] = p.Types
+		}
+	})
+	return &packageImporter{
+		loaded:   loaded,
+		fallback: importer.ForCompiler(pkg.Fset, "gc", exportLookup(pkg.Dir)),
+	}
+}
+
+func (i *packageImporter) Import(path string) (*types.Package, error) {
+	if p, ok := i.loaded[path]; ok {
+		return p, nil
+	}
+	return i.fallback.Import(path)
+}
+
+// exportFiles caches the export data files found by exportLookup, keyed by
+// directory and import path.
+var exportFiles sync.Map
+
+// exportLookup returns an importer.Lookup that finds the export data of a
+// package with "go list -export", resolving import paths in the module of dir.
+func exportLookup(dir string) importer.Lookup {
+	return func(path string) (io.ReadCloser, error) {
+		key := [2]string{dir, path}
+		if file, ok := exportFiles.Load(key); ok {
+			return os.Open(file.(string))
+		}
+
+		cmd := exec.Command("go", "list", "-export", "-f", "{{.Export}}", "--", path)
+		cmd.Dir = dir
+		var stderr strings.Builder
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("go list %s: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+		}
+		file := strings.TrimSpace(string(out))
+		if file == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+
+		exportFiles.Store(key, file)
+		return os.Open(file)
+	}
+}
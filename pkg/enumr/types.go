@@ -2,14 +2,25 @@ package enumr
 
 import (
 	"go/ast"
+	"go/token"
 )
 
 // enumData is used to pass the necessary data to the template.
 type enumData struct {
+	Header      string
 	PackageName string
+	InputHash   string
+	Imports     []importSpec
 	Enums       []enumInfo
 }
 
+// importSpec is an import of the generated file.
+type importSpec struct {
+	// Name is the explicit package name of the import, if any.
+	Name string
+	Path string
+}
+
 // enumInfo holds data for a specific enum type.
 type enumInfo struct {
 	TypeName     string
@@ -17,10 +28,40 @@ type enumInfo struct {
 	CaseFormat   string
 	GenerateVars bool
 	IncludeZero  bool
+	// Default is the name of the instance the zero value stands for, if any.
+	Default   string
+	ParseMode parseMode
+	// SQL generates database/sql Value and Scan methods.
+	SQL bool
+	// UnknownField is the string field that keeps unknown text when
+	// decoding, or empty if decoding is strict.
+	UnknownField string
+	// IDs reports whether the instances have stable IDs from @id attributes.
+	IDs bool
+	// Ordinal generates methods that navigate the instances in order.
+	Ordinal bool
+	// Wrap makes Next and Prev wrap around at the ends.
+	Wrap bool
+	// Set generates a <Type>Set bitset, indexed by ordinal.
+	Set bool
+	// Map generates a generic <Type>Map array, indexed by ordinal.
+	Map bool
+	// Match generates <Type>Cases and the Match<Type> functions.
+	Match bool
+	// ExcludeDeprecated leaves deprecated instances out of <Type>Values.
+	ExcludeDeprecated bool
+	// Runtime returns the error types of github.com/jmfrees/go-enumr instead
+	// of errors built with fmt.
+	Runtime      bool
 	MarshalField string
 	StructFields []fieldInfo
 }
 
+// SetWords returns the number of 64-bit words of the <Type>Set bitset.
+func (e enumInfo) SetWords() int {
+	return (len(e.Instances) + 63) / 64
+}
+
 // typeSpec holds information about a parsed type definition.
 type typeSpec struct {
 	PackageName string
@@ -50,6 +91,17 @@ type instanceResolution struct {
 
 // instanceData holds information about each constant instance.
 type instanceData struct {
-	Name   string
-	Fields map[string]string
+	Name       string
+	Fields     map[string]string
+	Attributes attributes
+	// Aliases are additional strings that parse to the instance.
+	Aliases []string
+	// ID is the stable ID of the instance, if the enum has IDs.
+	ID int
+	// Deprecated is the deprecation notice of a deprecated instance.
+	Deprecated string
+	// Doc is the doc comment of the var that declares a manual instance.
+	Doc *ast.CommentGroup
+	// Pos is the position of the directive or var that declares the instance.
+	Pos token.Position
 }
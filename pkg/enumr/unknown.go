@@ -0,0 +1,48 @@
+package enumr
+
+// resolveUnknownField sets UnknownField from the @unknown attribute of the
+// type, which names the string field that keeps text not matching any
+// instance when decoding. The field may not be the marshal field, and no
+// instance may set it.
+func (e *enumInfo) resolveUnknownField(attrs attributes) error {
+	attr, ok := attrs["unknown"]
+	if !ok {
+		return nil
+	}
+
+	field := attr.Value
+	if field == "" {
+		return errorAt(RuleInvalidAttribute, attr.Pos, "@unknown needs the name of a string field, e.g. @unknown=raw")
+	}
+	if !hasStringField(e.StructFields, field) {
+		return errorAt(RuleInvalidAttribute, attr.Pos, "@unknown field %q is not a string field of %s", field, e.TypeName)
+	}
+	if field == e.MarshalField {
+		return errorAt(RuleInvalidAttribute, attr.Pos, "@unknown field %q cannot be the marshal field", field)
+	}
+	for _, instance := range e.Instances {
+		if value, set := instance.Fields[field]; set && value != `""` {
+			return errorAt(
+				RuleInvalidAttribute,
+				instance.Pos,
+				"instance %s sets %q, which is reserved for unknown values by @unknown",
+				instance.Name,
+				field,
+			)
+		}
+	}
+
+	e.UnknownField = field
+	return nil
+}
+
+// hasStringField reports whether fields contains a field of type string with
+// the given name.
+func hasStringField(fields []fieldInfo, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return field.Type == "string"
+		}
+	}
+	return false
+}
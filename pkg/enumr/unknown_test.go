@@ -0,0 +1,52 @@
+package enumr
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestResolveUnknownField(t *testing.T) {
+	pos := token.Position{Filename: "method.go", Line: 3, Column: 9}
+	fields := []fieldInfo{{Name: "Code", Type: "string"}, {Name: "raw", Type: "string"}, {Name: "fee", Type: "int"}}
+	tests := []struct {
+		name      string
+		value     string
+		marshal   string
+		instances []instanceData
+		wantErr   bool
+	}{
+		{name: "String field", value: "raw", instances: []instanceData{{Name: "Card", Fields: map[string]string{"Code": `"CC"`}}}},
+		{name: "Missing value", value: "", wantErr: true},
+		{name: "Missing field", value: "other", wantErr: true},
+		{name: "Not a string", value: "fee", wantErr: true},
+		{name: "Marshal field", value: "Code", marshal: "Code", wantErr: true},
+		{
+			name:      "Set by instance",
+			value:     "raw",
+			instances: []instanceData{{Name: "Card", Fields: map[string]string{"raw": `"x"`}, Pos: pos}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enum := enumInfo{TypeName: "Method", MarshalField: tt.marshal, StructFields: fields, Instances: tt.instances}
+			err := enum.resolveUnknownField(attributes{"unknown": {Value: tt.value, Pos: pos}})
+			if tt.wantErr {
+				diags := Diagnostics(err)
+				if len(diags) != 1 || diags[0].Rule != RuleInvalidAttribute || diags[0].Pos != pos {
+					t.Errorf("resolveUnknownField() error = %v; want %s at %v", err, RuleInvalidAttribute, pos)
+				}
+				return
+			}
+			if err != nil || enum.UnknownField != tt.value {
+				t.Errorf("resolveUnknownField() = %q, %v; want %q", enum.UnknownField, err, tt.value)
+			}
+		})
+	}
+
+	enum := enumInfo{TypeName: "Method", StructFields: fields}
+	if err := enum.resolveUnknownField(attributes{}); err != nil || enum.UnknownField != "" {
+		t.Errorf("resolveUnknownField() without attribute = %q, %v; want strict decoding", enum.UnknownField, err)
+	}
+}
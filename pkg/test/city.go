@@ -0,0 +1,10 @@
+package test
+
+// The alias of SaoPaulo is written in NFD, which Parse matches in either form.
+//
+// enumr:@parse=nfc
+// enumr:Zurich   code:1 @alias=zürich
+// enumr:SaoPaulo code:2 @alias=são_paulo
+type City struct {
+	code int
+}
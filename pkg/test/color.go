@@ -0,0 +1,13 @@
+package test
+
+// enumr:@parse=fold,trim
+// enumr:@unknown=raw
+// enumr:@wrap
+// enumr:Red      hex:"#ff0000" @id=1 @alias=crimson,scarlet
+// enumr:DarkBlue hex:"#00008b" @id=2
+// enumr:Maroon   hex:"#800000" @id=4 @deprecated="use Red"
+// enumr:Unset    @id=0 @default
+type Color struct {
+	hex string
+	raw string
+}
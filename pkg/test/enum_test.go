@@ -1,7 +1,13 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
 	"testing"
+
+	"github.com/jmfrees/go-enumr"
 )
 
 // This test file assumes that go generate has been run and test_string.go exists.
@@ -50,3 +56,410 @@ func TestEnumUnmarshalText(t *testing.T) {
 		}
 	}
 }
+
+func TestParseModes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Color
+		wantErr  bool
+	}{
+		{"dark_blue", DarkBlue, false},
+		{"Dark_Blue", DarkBlue, false},
+		{"  RED\n", Red, false},
+		{"dark blue", Color{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseColor(test.input)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseColor(%q) error = %v, wantErr %v", test.input, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && result != test.expected {
+			t.Errorf("ParseColor(%q) = %v; want %v", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestParseNormalized(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected City
+		wantErr  bool
+	}{
+		{"zurich", Zurich, false},
+		{"z\u00fcrich", Zurich, false},
+		{"zu\u0308rich", Zurich, false},
+		{"sa\u0303o_paulo", SaoPaulo, false},
+		{"s\u00e3o_paulo", SaoPaulo, false},
+		{"Z\u00fcrich", City{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseCity(test.input)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseCity(%q) error = %v, wantErr %v", test.input, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && result != test.expected {
+			t.Errorf("ParseCity(%q) = %v; want %v", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	if got, err := ParseType("old_foo"); err != nil || got != Foo {
+		t.Errorf("ParseType(%q) = %v, %v; want %v", "old_foo", got, err, Foo)
+	}
+	if got, err := ParseColor(" Scarlet "); err != nil || got != Red {
+		t.Errorf("ParseColor(%q) = %v, %v; want %v", " Scarlet ", got, err, Red)
+	}
+	if got := Foo.String(); got != "foo" {
+		t.Errorf("Foo.String() = %q; want the canonical %q", got, "foo")
+	}
+}
+
+func TestDeprecatedHook(t *testing.T) {
+	var parsed []string
+	ColorDeprecatedHook = func(value Color, text string) {
+		if value != Maroon {
+			t.Errorf("ColorDeprecatedHook called with %v; want %v", value, Maroon)
+		}
+		parsed = append(parsed, text)
+	}
+	defer func() { ColorDeprecatedHook = nil }()
+
+	for _, text := range []string{"red", "MAROON"} {
+		if _, err := ParseColor(text); err != nil {
+			t.Errorf("ParseColor(%q) failed: %v", text, err)
+		}
+	}
+	if len(parsed) != 1 || parsed[0] != "MAROON" {
+		t.Errorf("ColorDeprecatedHook calls = %q; want [\"MAROON\"]", parsed)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := ParseType("fooo")
+	if !errors.Is(err, enumr.ErrUnknownValue) {
+		t.Fatalf("ParseType(%q) error = %v; want ErrUnknownValue", "fooo", err)
+	}
+
+	var unknown *enumr.UnknownValueError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("ParseType(%q) error is %T; want *enumr.UnknownValueError", "fooo", err)
+	}
+	if unknown.Type != "Type" || unknown.Value != "fooo" || unknown.Suggestion != "foo" {
+		t.Errorf("UnknownValueError = %+v; want Type, fooo and suggestion foo", unknown)
+	}
+	if want := `unknown Type value "fooo"; did you mean "foo"?`; err.Error() != want {
+		t.Errorf("Error() = %q; want %q", err.Error(), want)
+	}
+}
+
+func TestDefaultInstance(t *testing.T) {
+	if got := (Color{}).String(); got != "unset" {
+		t.Errorf("Color{}.String() = %q; want %q", got, "unset")
+	}
+	if !Unset.IsZero() || Red.IsZero() {
+		t.Errorf("IsZero() = %v for Unset and %v for Red; want true and false", Unset.IsZero(), Red.IsZero())
+	}
+
+	type document struct {
+		Color Color `json:"color,omitzero"`
+		Other Color `json:"other"`
+	}
+	data, err := json.Marshal(document{})
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if want := `{"other":"unset"}`; string(data) != want {
+		t.Errorf("json.Marshal = %s; want %s", data, want)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	var color Color
+	if err := color.Scan(nil); err != nil || color != Unset {
+		t.Errorf("Scan(nil) = %v, %v; want %v", color, err, Unset)
+	}
+	if err := color.Scan([]byte("Red")); err != nil || color != Red {
+		t.Errorf("Scan(%q) = %v, %v; want %v", "Red", color, err, Red)
+	}
+	if value, err := Red.Value(); err != nil || value != "red" {
+		t.Errorf("Red.Value() = %v, %v; want %q", value, err, "red")
+	}
+
+	if value, err := (Type{}).Value(); err != nil || value != nil {
+		t.Errorf("Type{}.Value() = %v, %v; want NULL", value, err)
+	}
+	var typ Type
+	if err := typ.Scan(42); err == nil {
+		t.Errorf("Scan(42) succeeded; want an error")
+	}
+}
+
+func TestUnknownValues(t *testing.T) {
+	var doc struct {
+		Color Color `json:"color"`
+	}
+	if err := json.Unmarshal([]byte(`{"color":"Chartreuse"}`), &doc); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if doc.Color.IsKnown() {
+		t.Errorf("IsKnown() = true for %v; want false", doc.Color)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if want := `{"color":"Chartreuse"}`; string(data) != want {
+		t.Errorf("json.Marshal = %s; want the unknown value unchanged, %s", data, want)
+	}
+
+	for _, color := range []Color{Red, Unset, {}} {
+		if !color.IsKnown() {
+			t.Errorf("IsKnown() = false for %v; want true", color)
+		}
+	}
+	if _, err := ParseColor("Chartreuse"); !errors.Is(err, enumr.ErrUnknownValue) {
+		t.Errorf("ParseColor(%q) error = %v; want ErrUnknownValue", "Chartreuse", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, value := range []Type{Foo, LongerName} {
+		if !value.IsValid() || value.Validate() != nil {
+			t.Errorf("%v: IsValid() = %v, Validate() = %v; want valid", value, value.IsValid(), value.Validate())
+		}
+	}
+
+	for _, value := range []Type{{}, {v1: 100, v2: "x"}} {
+		if value.IsValid() {
+			t.Errorf("IsValid() = true for %+v; want false", value)
+		}
+		var unknown *enumr.UnknownValueError
+		if err := value.Validate(); !errors.As(err, &unknown) || unknown.Type != "Type" {
+			t.Errorf("Validate() = %v for %+v; want an *enumr.UnknownValueError", err, value)
+		}
+	}
+
+	err := Type{v1: 99}.Validate()
+	if want := `unknown Type value "{v1:99 v2:}"`; err == nil || err.Error() != want {
+		t.Errorf("Validate() = %v; want %s", err, want)
+	}
+	err = Color{raw: "purple"}.Validate()
+	if want := `unknown Color value "purple"`; err == nil || err.Error() != want {
+		t.Errorf("Validate() = %v; want %s", err, want)
+	}
+
+	if !(Color{}).IsValid() {
+		t.Errorf("Color{}.IsValid() = false; want true as it stands for Unset")
+	}
+}
+
+func TestOrdinals(t *testing.T) {
+	if TypeCount != 4 || TypeMin() != Foo || TypeMax() != LongerName {
+		t.Errorf("TypeCount, TypeMin, TypeMax = %d, %v, %v; want 4, foo, longer_name", TypeCount, TypeMin(), TypeMax())
+	}
+	for i, value := range TypeValues() {
+		if value.Ordinal() != i {
+			t.Errorf("%v.Ordinal() = %d; want %d", value, value.Ordinal(), i)
+		}
+		if got, ok := TypeFromOrdinal(i); !ok || got != value {
+			t.Errorf("TypeFromOrdinal(%d) = %v, %v; want %v", i, got, ok, value)
+		}
+	}
+	if _, ok := TypeFromOrdinal(TypeCount); ok {
+		t.Errorf("TypeFromOrdinal(%d) succeeded; want false", TypeCount)
+	}
+	if (Type{}).Ordinal() != -1 {
+		t.Errorf("Type{}.Ordinal() = %d; want -1", (Type{}).Ordinal())
+	}
+
+	values := []Type{LongerName, Foo, Baz, Bar}
+	slices.SortFunc(values, Type.Compare)
+	if !slices.Equal(values, TypeValues()) {
+		t.Errorf("sorted values = %v; want %v", values, TypeValues())
+	}
+
+	if next, ok := Bar.Next(); !ok || next != Baz {
+		t.Errorf("Bar.Next() = %v, %v; want %v", next, ok, Baz)
+	}
+	if _, ok := LongerName.Next(); ok {
+		t.Errorf("LongerName.Next() succeeded; want false without @wrap")
+	}
+	if _, ok := Foo.Prev(); ok {
+		t.Errorf("Foo.Prev() succeeded; want false without @wrap")
+	}
+
+	if next, ok := ColorMax().Next(); !ok || next != ColorMin() {
+		t.Errorf("ColorMax().Next() = %v, %v; want %v with @wrap", next, ok, ColorMin())
+	}
+	if prev, ok := Red.Prev(); !ok || prev != Unset {
+		t.Errorf("Red.Prev() = %v, %v; want %v with @wrap", prev, ok, Unset)
+	}
+}
+
+func TestIDs(t *testing.T) {
+	if Maroon.ID() != 4 || (Color{}).ID() != 0 {
+		t.Errorf("Maroon.ID(), Color{}.ID() = %d, %d; want 4, 0", Maroon.ID(), (Color{}).ID())
+	}
+	if got := ColorValues(); !slices.Equal(got, []Color{Unset, Red, DarkBlue, Maroon}) {
+		t.Errorf("ColorValues() = %v; want values ordered by ID", got)
+	}
+
+	data, err := Maroon.MarshalBinary()
+	if err != nil || !bytes.Equal(data, []byte{4}) {
+		t.Fatalf("Maroon.MarshalBinary() = %v, %v; want [4]", data, err)
+	}
+	var color Color
+	if err = color.UnmarshalBinary(data); err != nil || color != Maroon {
+		t.Errorf("UnmarshalBinary(%v) = %v, %v; want %v", data, color, err, Maroon)
+	}
+	if err = color.UnmarshalBinary([]byte{3}); !errors.Is(err, enumr.ErrUnknownValue) {
+		t.Errorf("UnmarshalBinary of retired ID 3 error = %v; want ErrUnknownValue", err)
+	}
+	if _, err = (Color{raw: "Chartreuse"}).MarshalBinary(); err == nil {
+		t.Errorf("MarshalBinary of an unknown value succeeded; want an error")
+	}
+}
+
+func TestSet(t *testing.T) {
+	set := NewTypeSet(Baz, Foo)
+	set.Add(Type{})
+	if set.Len() != 2 || !set.Has(Foo) || set.Has(Bar) {
+		t.Errorf("NewTypeSet(Baz, Foo) = %v; want {Foo, Baz}", slices.Collect(set.All()))
+	}
+	if got := slices.Collect(set.All()); !slices.Equal(got, []Type{Foo, Baz}) {
+		t.Errorf("All() = %v; want values in ordinal order", got)
+	}
+
+	other := NewTypeSet(Baz, LongerName)
+	for _, tt := range []struct {
+		name     string
+		got      TypeSet
+		expected []Type
+	}{
+		{name: "Union", got: set.Union(other), expected: []Type{Foo, Baz, LongerName}},
+		{name: "Intersect", got: set.Intersect(other), expected: []Type{Baz}},
+		{name: "Difference", got: set.Difference(other), expected: []Type{Foo}},
+	} {
+		if got := slices.Collect(tt.got.All()); !slices.Equal(got, tt.expected) {
+			t.Errorf("%s() = %v; want %v", tt.name, got, tt.expected)
+		}
+	}
+
+	set.Remove(Foo)
+	if set != NewTypeSet(Baz) {
+		t.Errorf("after Remove(Foo) set = %v; want {Baz}", slices.Collect(set.All()))
+	}
+
+	data, err := json.Marshal(NewColorSet(DarkBlue, Red))
+	if err != nil || string(data) != `["red","dark_blue"]` {
+		t.Fatalf("json.Marshal = %s, %v; want [\"red\",\"dark_blue\"]", data, err)
+	}
+	var colors ColorSet
+	if err = json.Unmarshal(data, &colors); err != nil || colors != NewColorSet(Red, DarkBlue) {
+		t.Errorf("json.Unmarshal(%s) = %v, %v", data, slices.Collect(colors.All()), err)
+	}
+	if err = json.Unmarshal([]byte(`["red","purple"]`), &colors); !errors.Is(err, enumr.ErrUnknownValue) {
+		t.Errorf("json.Unmarshal(%s) error = %v; want ErrUnknownValue", `["red","purple"]`, err)
+	}
+
+	text, err := NewTypeSet(Bar, LongerName).MarshalText()
+	if err != nil || string(text) != "bar,longer_name" {
+		t.Fatalf("MarshalText() = %q, %v; want %q", text, err, "bar,longer_name")
+	}
+	var types TypeSet
+	if err = types.UnmarshalText(text); err != nil || types != NewTypeSet(Bar, LongerName) {
+		t.Errorf("UnmarshalText(%q) = %v, %v", text, slices.Collect(types.All()), err)
+	}
+	if err = types.UnmarshalText([]byte("bar,qux")); !errors.Is(err, enumr.ErrUnknownValue) {
+		t.Errorf("UnmarshalText(%q) error = %v; want ErrUnknownValue", "bar,qux", err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	var limits TypeMap[int]
+	limits.Set(Baz, 3)
+	limits.Set(Foo, 1)
+	limits.Set(Type{}, 99)
+	if got, ok := limits.Get(Foo); !ok || got != 1 {
+		t.Errorf("Get(Foo) = %d, %v; want 1, true", got, ok)
+	}
+	if _, ok := limits.Get(Bar); ok {
+		t.Errorf("Get(Bar) reports a value; want none")
+	}
+	if limits.Len() != 2 {
+		t.Errorf("Len() = %d; want 2", limits.Len())
+	}
+	if got := slices.Collect(limits.Keys()); !slices.Equal(got, []Type{Foo, Baz}) {
+		t.Errorf("Keys() = %v; want keys in ordinal order", got)
+	}
+	if got := slices.Collect(limits.Values()); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Values() = %v; want [1 3]", got)
+	}
+
+	data, err := json.Marshal(limits)
+	if err != nil || string(data) != `{"foo":1,"baz":3}` {
+		t.Fatalf("json.Marshal = %s, %v; want {\"foo\":1,\"baz\":3}", data, err)
+	}
+	var decoded TypeMap[int]
+	if err = json.Unmarshal(data, &decoded); err != nil || decoded != limits {
+		t.Errorf("json.Unmarshal(%s) = %v, %v; want %v", data, decoded, err, limits)
+	}
+	type config struct {
+		Limits TypeMap[int] `json:"limits"`
+	}
+	data, err = json.Marshal(config{Limits: limits})
+	if want := `{"limits":{"foo":1,"baz":3}}`; err != nil || string(data) != want {
+		t.Errorf("json.Marshal of struct field = %s, %v; want %s", data, err, want)
+	}
+	if err = json.Unmarshal([]byte(`{"qux":1}`), &decoded); !errors.Is(err, enumr.ErrUnknownValue) {
+		t.Errorf("json.Unmarshal with unknown key error = %v; want ErrUnknownValue", err)
+	}
+	var cities CityMap[int]
+	err = json.Unmarshal([]byte(`{"zürich":1,"zurich":2}`), &cities)
+	if want := `keys "zurich" and "zürich" of CityMap both parse to zurich`; err == nil || err.Error() != want {
+		t.Errorf("json.Unmarshal with duplicate keys error = %v; want %q", err, want)
+	}
+
+	limits.Delete(Foo)
+	if _, ok := limits.Get(Foo); ok || limits.Len() != 1 {
+		t.Errorf("after Delete(Foo) Len() = %d; want 1 without Foo", limits.Len())
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cases := TypeCases[int]{
+		Foo:        func() int { return 1 },
+		Bar:        func() int { return 2 },
+		Baz:        func() int { return 3 },
+		LongerName: func() int { return 4 },
+	}
+	cases.MustBeExhaustive()
+	if got := MustMatchType(Baz, cases); got != 3 {
+		t.Errorf("MustMatchType(Baz) = %d; want 3", got)
+	}
+	if _, err := MatchType(Type{}, cases); !errors.Is(err, enumr.ErrUnknownValue) {
+		t.Errorf("MatchType(Type{}) error = %v; want ErrUnknownValue", err)
+	}
+
+	partial := ColorCases[string]{Red: func() string { return "stop" }}
+	if got, err := MatchColor(Red, partial); err != nil || got != "stop" {
+		t.Errorf("MatchColor(Red) = %q, %v; want %q", got, err, "stop")
+	}
+	if _, err := MatchColor(DarkBlue, partial); !errors.Is(err, enumr.ErrNoHandler) {
+		t.Errorf("MatchColor(DarkBlue) error = %v; want ErrNoHandler", err)
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		want := `no handler for Color values Unset, DarkBlue, Maroon`
+		if !errors.Is(err, enumr.ErrNoHandler) || err.Error() != want {
+			t.Errorf("MustBeExhaustive() panicked with %v; want %q", err, want)
+		}
+	}()
+	partial.MustBeExhaustive()
+}
@@ -1,12 +1,13 @@
 package test
 
-//go:generate ./../../enumr -type=Type -format=snake_case
+//go:generate ./../../enumr -type=Type,Color,City -format=snake_case -sql -set -map -match -runtime
 type Type struct {
 	v1 int
 	v2 string
 }
 
 var (
+	// enumr:@alias=fu,old_foo
 	Foo        = Type{100, "a"}
 	Bar        = Type{200, "b"}
 	Baz        = Type{300, "c"}